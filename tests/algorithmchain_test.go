@@ -0,0 +1,170 @@
+//go:build algorithmchain_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// courseChain is the routing from the request: trial division below 2^20, rho below 2^45.
+var courseChain = []Rule{
+	{MaxBits: 20, Algorithm: AlgorithmTrialDivision},
+	{MaxBits: 45, Algorithm: AlgorithmRho},
+}
+
+func TestAlgorithmChainInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name  string
+		rules []Rule
+		want  string
+	}{
+		{name: "empty", rules: nil, want: "algorithm chain"},
+		{name: "zero bits", rules: []Rule{{MaxBits: 0, Algorithm: AlgorithmRho}}, want: "0"},
+		{name: "too many bits", rules: []Rule{{MaxBits: 65, Algorithm: AlgorithmRho}}, want: "65"},
+		{name: "unknown algorithm", rules: []Rule{{MaxBits: 20, Algorithm: Algorithm(100)}}, want: "100"},
+		{
+			name: "not increasing",
+			rules: []Rule{
+				{MaxBits: 45, Algorithm: AlgorithmRho},
+				{MaxBits: 20, Algorithm: AlgorithmTrialDivision},
+			},
+			want: "20",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(WithAlgorithmChain(tt.rules...))
+
+			require.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestAlgorithmChainRouting(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(WithAlgorithmChain(courseChain...))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		n    int
+		want Algorithm
+	}{
+		{n: 0, want: AlgorithmTrialDivision},
+		{n: 1<<20 - 1, want: AlgorithmTrialDivision},
+		{n: -(1<<20 - 1), want: AlgorithmTrialDivision},
+		{n: 1 << 20, want: AlgorithmRho},
+		{n: 1<<45 - 1, want: AlgorithmRho},
+		{n: -(1<<45 - 1), want: AlgorithmRho},
+	}
+
+	for _, tt := range testCases {
+		t.Run(strconv.Itoa(tt.n), func(t *testing.T) {
+			got, err := fact.AlgorithmFor(tt.n)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, n := range []int{1 << 45, math.MaxInt, math.MinInt} {
+		_, err := fact.AlgorithmFor(n)
+
+		var unrouted *UnroutedInputError
+		require.True(t, errors.As(err, &unrouted), "n = %d: got %v", n, err)
+		require.Equal(t, n, unrouted.N)
+	}
+}
+
+func TestAlgorithmString(t *testing.T) {
+	require.Equal(t, "trial division", AlgorithmTrialDivision.String())
+	require.Equal(t, "rho", AlgorithmRho.String())
+}
+
+func TestAlgorithmChainGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithAlgorithmChain(courseChain...),
+	)
+	require.NoError(t, err)
+
+	// the semiprime is above 2^20 and is routed to rho
+	numbers := []int{100, -17, 1<<20 + 7, 17592102158387}
+	want := []string{
+		"100 = 2 * 2 * 5 * 5",
+		"-17 = -1 * 17",
+		"1048583 = 1048583",
+		"17592102158387 = 4194287 * 4194301",
+	}
+
+	writer := newWriter()
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	facts := getFact(writer)
+	slices.Sort(facts)
+	slices.Sort(want)
+
+	require.Equal(t, want, facts)
+}
+
+func TestAlgorithmChainUnroutedInput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithAlgorithmChain(courseChain...),
+	)
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	err = fact.Factorize(context.Background(), []int{12, math.MaxInt, 13}, newWriter())
+
+	var unrouted *UnroutedInputError
+	require.True(t, errors.As(err, &unrouted), "got %v", err)
+	require.Equal(t, math.MaxInt, unrouted.N)
+	require.NotErrorIs(t, err, ErrWriterInteraction)
+	require.NotErrorIs(t, err, ErrFactorizationCancelled)
+
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestAlgorithmChainCatchAll(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(4),
+		WithAlgorithmChain(append(slices.Clone(courseChain), Rule{MaxBits: 64, Algorithm: AlgorithmRho})...),
+	)
+	require.NoError(t, err)
+
+	numbers := append(generateNumbers(10_000), math.MinInt, math.MaxInt)
+
+	writer := newWriter()
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	require.Len(t, lines, len(numbers))
+
+	for _, line := range lines {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res), "line %q", line)
+	}
+}
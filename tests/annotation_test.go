@@ -0,0 +1,121 @@
+//go:build annotation_test
+
+package fact
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrimalityAnnotationGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name    string
+		numbers []int
+		want    []string
+	}{
+		{
+			name:    "readme example",
+			numbers: []int{100, -17, 25, 38},
+			want: []string{
+				"100 = 2 * 2 * 5 * 5 (composite)",
+				"-17 = -1 * 17 (prime)",
+				"25 = 5 * 5 (composite)",
+				"38 = 2 * 19 (composite)",
+			},
+		},
+		{
+			// the sign is ignored, ±1 are units, and zero is composite, as in IsPrime and TestPrimality
+			name:    "units and zero",
+			numbers: []int{1, -1, 0},
+			want: []string{
+				"1 = 1 (unit)",
+				"-1 = -1 (unit)",
+				"0 = 0 (composite)",
+			},
+		},
+		{
+			name:    "primes",
+			numbers: []int{2, 3, -2, math.MaxInt32},
+			want: []string{
+				"2 = 2 (prime)",
+				"3 = 3 (prime)",
+				"-2 = -1 * 2 (prime)",
+				"2147483647 = 2147483647 (prime)",
+			},
+		},
+		{
+			name:    "prime powers",
+			numbers: []int{4, 8, -9},
+			want: []string{
+				"4 = 2 * 2 (composite)",
+				"8 = 2 * 2 * 2 (composite)",
+				"-9 = -1 * 3 * 3 (composite)",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			fact, err := New(
+				WithFactorizationWorkers(3),
+				WithWriteWorkers(3),
+				WithPrimalityAnnotation(),
+			)
+			require.NoError(t, err)
+
+			writer := newWriter()
+			err = fact.Factorize(context.Background(), tt.numbers, writer)
+			require.NoError(t, err)
+
+			facts := getFact(writer)
+			slices.Sort(facts)
+			slices.Sort(tt.want)
+
+			require.Equal(t, tt.want, facts)
+		})
+	}
+}
+
+func TestPrimalityAnnotationCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(10),
+		WithPrimalityAnnotation(),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(100_000)[2:]
+	writer := newWriter()
+
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	allNums := make([]int, 0, len(numbers))
+	for _, line := range getFact(writer) {
+		line, verdict, ok := strings.Cut(line, " (")
+		require.True(t, ok, "line %q has no verdict", line)
+
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+
+		if len(res) == 1 {
+			require.Equal(t, "prime)", verdict, "%d", num)
+		} else {
+			require.Equal(t, "composite)", verdict, "%d", num)
+		}
+
+		allNums = append(allNums, num)
+	}
+
+	slices.Sort(allNums)
+	require.Equal(t, numbers, allNums)
+}
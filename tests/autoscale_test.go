@@ -0,0 +1,114 @@
+//go:build autoscale_test
+
+package fact
+
+import (
+	"context"
+	"runtime/debug"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoScaleInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	t.Run("min", func(t *testing.T) {
+		_, err := New(WithAutoScale(0, 10))
+
+		require.ErrorContains(t, err, "autoscale")
+		require.ErrorContains(t, err, "0")
+	})
+
+	t.Run("max below min", func(t *testing.T) {
+		_, err := New(WithAutoScale(10, 5))
+
+		require.ErrorContains(t, err, "autoscale")
+		require.ErrorContains(t, err, "5")
+	})
+}
+
+func TestAutoScaleCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(WithAutoScale(1, 64))
+	require.NoError(t, err)
+
+	numbers := generateNumbers(100_000)
+	writer := newWriter()
+
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	allNums := make([]int, 0, len(numbers))
+	for _, line := range getFact(writer) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		allNums = append(allNums, num)
+	}
+
+	slices.Sort(allNums)
+	require.Equal(t, numbers, allNums)
+}
+
+func TestAutoScaleGrowsForSlowWriter(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const maxWorkers = 100
+
+	fact, err := New(WithAutoScale(1, maxWorkers))
+	require.NoError(t, err)
+
+	debug.SetGCPercent(-1)
+	t.Cleanup(func() {
+		debug.SetGCPercent(100)
+	})
+
+	start := time.Now()
+
+	gNum := inspectNumGoroutines(t, func() {
+		err := fact.Factorize(context.Background(), generateNumbers(200), newSleepWriter(time.Millisecond*100))
+		require.NoError(t, err)
+	})
+
+	// a single write worker would need 20 seconds
+	require.Less(t, time.Since(start), time.Second*5)
+	require.Greater(t, gNum, 10)
+	require.LessOrEqual(t, gNum, 2*maxWorkers+50)
+}
+
+func TestAutoScaleStaysSmallForTinyBatch(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const minWorkers = 2
+
+	fact, err := New(WithAutoScale(minWorkers, 1000))
+	require.NoError(t, err)
+
+	debug.SetGCPercent(-1)
+	t.Cleanup(func() {
+		debug.SetGCPercent(100)
+	})
+
+	gNum := inspectNumGoroutines(t, func() {
+		err := fact.Factorize(context.Background(), generateNumbers(10), newWriter())
+		require.NoError(t, err)
+	})
+
+	require.LessOrEqual(t, gNum, 2*minWorkers+50)
+}
+
+func TestAutoScaleCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(WithAutoScale(1, 1000))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	err = fact.Factorize(ctx, generateNumbers(1_000_000), newSleepWriter(time.Millisecond))
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+}
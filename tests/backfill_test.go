@@ -0,0 +1,160 @@
+//go:build backfill_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name     string
+		numbers  []int
+		existing string
+		want     []string
+	}{
+		{
+			name:     "complete",
+			numbers:  []int{100, -17, 25},
+			existing: "100 = 2 * 2 * 5 * 5\n-17 = -1 * 17\n25 = 5 * 5\n",
+			want:     []string{},
+		},
+		{
+			name:     "empty existing",
+			numbers:  []int{4, 5},
+			existing: "",
+			want: []string{
+				"4 = 2 * 2",
+				"5 = 5",
+			},
+		},
+		{
+			name:     "partial",
+			numbers:  []int{10, 4, 12, 15},
+			existing: "4 = 2 * 2\n15 = 3 * 5\n",
+			want: []string{
+				"10 = 2 * 5",
+				"12 = 2 * 2 * 3",
+			},
+		},
+		{
+			name:     "failed verification",
+			numbers:  []int{12, 27, 33},
+			existing: "12 = 2 * 6\n27 = 3 * 3 * 3\n33 = 11 * 3\n",
+			want: []string{
+				"12 = 2 * 2 * 3",
+				"33 = 3 * 11",
+			},
+		},
+		{
+			name:     "repeated inputs",
+			numbers:  []int{4, 4, 4},
+			existing: "4 = 2 * 2\n",
+			want: []string{
+				"4 = 2 * 2",
+				"4 = 2 * 2",
+			},
+		},
+		{
+			name:     "truncated tail",
+			numbers:  []int{38, 19},
+			existing: "19 = 19\n38 = 2 *",
+			want: []string{
+				"38 = 2 * 19",
+			},
+		},
+		{
+			name:     "garbage lines",
+			numbers:  []int{-20, 1},
+			existing: "hello\n\n1 = 1\n-20 = -1 * 2 * 2 * x\n",
+			want: []string{
+				"-20 = -1 * 2 * 2 * 5",
+			},
+		},
+		{
+			name:     "extra lines",
+			numbers:  []int{6},
+			existing: "7 = 7\n6 = 2 * 3\n",
+			want:     []string{},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			fact := newFactorizer(t, 3, 3)
+
+			writer := newWriter()
+			err := fact.Backfill(context.Background(), tt.numbers, strings.NewReader(tt.existing), writer)
+			require.NoError(t, err)
+
+			facts := getFact(writer)
+			slices.Sort(tt.want)
+			slices.Sort(facts)
+
+			require.Equal(t, tt.want, facts)
+		})
+	}
+}
+
+func TestBackfillCompletesOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := generateNumbers(10_000)
+	fact := newFactorizer(t, 10, 10)
+
+	first := newWriter()
+	err := fact.Factorize(context.Background(), numbers[:len(numbers)/2], first)
+	require.NoError(t, err)
+
+	second := newWriter()
+	err = fact.Backfill(context.Background(), numbers, strings.NewReader(first.String()), second)
+	require.NoError(t, err)
+
+	allNums := make([]int, 0, len(numbers))
+	for _, line := range append(getFact(first), getFact(second)...) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		allNums = append(allNums, num)
+	}
+
+	slices.Sort(allNums)
+	require.Equal(t, numbers, allNums)
+}
+
+func TestBackfillReaderError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	errRead := errors.New("existing output unavailable")
+	fact := newFactorizer(t, 1, 1)
+
+	writer := newWriter()
+	err := fact.Backfill(context.Background(), []int{1, 2, 3}, iotest.ErrReader(errRead), writer)
+	require.ErrorIs(t, err, errRead)
+	require.NotErrorIs(t, err, ErrWriterInteraction)
+
+	require.Zero(t, len(writer.String()))
+}
+
+func TestBackfillCancelWriterNoOp(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fact := newFactorizer(t, 1, 1)
+
+	writer := newWriter()
+	err := fact.Backfill(ctx, generateNumbers(1000), strings.NewReader(""), writer)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	require.Zero(t, len(writer.String()))
+}
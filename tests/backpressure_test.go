@@ -0,0 +1,111 @@
+//go:build backpressure_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type backpressureEvent struct {
+	pending    int
+	blockedFor time.Duration
+}
+
+type backpressureRecorder struct {
+	mx     sync.Mutex
+	events []backpressureEvent
+}
+
+func (r *backpressureRecorder) handle(pending int, blockedFor time.Duration) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.events = append(r.events, backpressureEvent{pending: pending, blockedFor: blockedFor})
+}
+
+func (r *backpressureRecorder) Events() []backpressureEvent {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	return slices.Clone(r.events)
+}
+
+func TestBackpressureInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithBackpressureHandler(nil))
+
+	require.ErrorContains(t, err, "backpressure")
+}
+
+func TestBackpressureSlowWriter(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const factWorkers = 4
+
+	rec := new(backpressureRecorder)
+
+	fact, err := New(
+		WithFactorizationWorkers(factWorkers),
+		WithWriteWorkers(1),
+		WithBackpressureHandler(rec.handle),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(20)
+	writer := newSleepWriter(time.Millisecond * 50)
+
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+	require.Len(t, getFact(writer), len(numbers))
+
+	events := rec.Events()
+	require.NotEmpty(t, events, "a slow writer must be reported")
+
+	var longest time.Duration
+	for _, event := range events {
+		require.Positive(t, event.pending)
+		require.LessOrEqual(t, event.pending, factWorkers, "only factorization workers can be blocked")
+		require.Positive(t, event.blockedFor)
+
+		longest = max(longest, event.blockedFor)
+	}
+
+	// factorization workers wait for the single write worker to finish its sleep
+	require.GreaterOrEqual(t, longest, time.Millisecond*40)
+}
+
+func TestBackpressureCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	rec := new(backpressureRecorder)
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(1),
+		WithBackpressureHandler(rec.handle),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	start := time.Now()
+
+	err = fact.Factorize(ctx, generateNumbers(1000), newSleepWriter(time.Millisecond*50))
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	require.Less(t, time.Since(start), time.Second)
+
+	events := len(rec.Events())
+
+	time.Sleep(time.Millisecond * 100)
+
+	require.Len(t, rec.Events(), events, "backpressure must not be reported after Factorize returns")
+}
@@ -0,0 +1,80 @@
+package fact
+
+import (
+	"context"
+	"io"
+	"math"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// workerCounts returns the worker counts each benchmark is scaled over.
+func workerCounts() []int {
+	counts := []int{1, 2, 4, 8}
+
+	if procs := runtime.GOMAXPROCS(-1); procs > counts[len(counts)-1] {
+		counts = append(counts, procs)
+	}
+
+	return counts
+}
+
+func benchmarkFactorize(b *testing.B, numbers []int, writer func() io.Writer, workers func(n int) (int, int)) {
+	b.Helper()
+
+	for _, n := range workerCounts() {
+		b.Run("workers="+strconv.Itoa(n), func(b *testing.B) {
+			factWorkers, writeWorkers := workers(n)
+			fact := newFactorizer(b, factWorkers, writeWorkers)
+			ctx := context.Background()
+
+			b.ReportAllocs()
+
+			for b.Loop() {
+				if err := fact.Factorize(ctx, numbers, writer()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func newDiscardWriter() io.Writer {
+	return io.Discard
+}
+
+func factWorkersScaled(n int) (int, int) {
+	return n, 1
+}
+
+func BenchmarkFactorizeSmall(b *testing.B) {
+	benchmarkFactorize(b, generateNumbers(100), newDiscardWriter, factWorkersScaled)
+}
+
+func BenchmarkFactorizeLarge(b *testing.B) {
+	benchmarkFactorize(b, generateNumbers(100_000), newDiscardWriter, factWorkersScaled)
+}
+
+func BenchmarkFactorizePrimeHeavy(b *testing.B) {
+	numbers := make([]int, 64)
+	for i := range numbers {
+		numbers[i] = math.MaxInt32
+		if i%2 == 1 {
+			numbers[i] = 2147483629
+		}
+	}
+
+	benchmarkFactorize(b, numbers, newDiscardWriter, factWorkersScaled)
+}
+
+func BenchmarkFactorizeWriterBound(b *testing.B) {
+	writer := func() io.Writer {
+		return newSleepWriter(time.Millisecond)
+	}
+
+	benchmarkFactorize(b, generateNumbers(100), writer, func(n int) (int, int) {
+		return 1, n
+	})
+}
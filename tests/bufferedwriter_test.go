@@ -0,0 +1,200 @@
+//go:build bufferedwriter_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type countingErrorWriter struct {
+	calls atomic.Int64
+	err   error
+}
+
+func (c *countingErrorWriter) Write(_ []byte) (n int, err error) {
+	c.calls.Add(1)
+
+	return 0, c.err
+}
+
+func TestBufferedWriterInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name string
+		size int
+		want string
+	}{
+		{name: "zero", size: 0, want: "0"},
+		{name: "negative", size: -10, want: "-10"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(WithBufferedWriter(tt.size))
+
+			require.ErrorContains(t, err, "buffer")
+			require.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestBufferedWriterFlushesCompleteLines(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name         string
+		size         int
+		writeWorkers int
+		numbers      []int
+	}{
+		{name: "tiny buffer", size: 1, writeWorkers: 1, numbers: generateNumbers(100)},
+		{name: "line sized buffer", size: 16, writeWorkers: 4, numbers: generateNumbers(1000)},
+		{name: "default sized buffer", size: 4096, writeWorkers: 10, numbers: generateNumbers(100_000)},
+		{name: "buffer larger than output", size: 1 << 20, writeWorkers: 3, numbers: []int{100, -17, 0, 1}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			fact, err := New(
+				WithFactorizationWorkers(4),
+				WithWriteWorkers(tt.writeWorkers),
+				WithBufferedWriter(tt.size),
+			)
+			require.NoError(t, err)
+
+			writer := newRecordingWriter()
+
+			err = fact.Factorize(context.Background(), tt.numbers, writer)
+			require.NoError(t, err)
+
+			calls := writer.Calls()
+			for _, call := range calls {
+				require.NotEmpty(t, call, "empty flush")
+				require.True(t, strings.HasSuffix(call, "\n"), "buffer %q flushed mid-line", call)
+
+				if strings.Count(call, "\n") > 1 {
+					require.LessOrEqual(t, len(call), tt.size, "buffer overflowed")
+				}
+			}
+
+			allNums := make([]int, 0, len(tt.numbers))
+			for _, line := range getFact(writer) {
+				num, res := parseLine(t, line)
+				require.True(t, checkFactorization(num, res))
+				allNums = append(allNums, num)
+			}
+
+			slices.Sort(allNums)
+			slices.Sort(tt.numbers)
+			require.Equal(t, tt.numbers, allNums)
+		})
+	}
+}
+
+func TestBufferedWriterReducesWrites(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const (
+		size         = 4096
+		writeWorkers = 4
+	)
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(writeWorkers),
+		WithBufferedWriter(size),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(10_000)
+	writer := newRecordingWriter()
+
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	// each buffer is at least half full when flushed, except for the final flush of every worker
+	require.LessOrEqual(t, len(writer.Calls()), 2*len(writer.String())/size+writeWorkers)
+}
+
+func TestBufferedWriterNoFlushAfterReturn(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(4),
+		WithBufferedWriter(1<<20),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(1000)
+	writer := newRecordingWriter()
+
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	calls := len(writer.Calls())
+	require.Len(t, getFact(writer), len(numbers), "buffers must be flushed before Factorize returns")
+
+	time.Sleep(time.Millisecond * 100)
+
+	require.Len(t, writer.Calls(), calls, "buffers must not be flushed after Factorize returns")
+}
+
+func TestBufferedWriterNoFlushAfterError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(1),
+		WithBufferedWriter(64),
+	)
+	require.NoError(t, err)
+
+	errWrite := errors.New("disk full")
+	writer := &countingErrorWriter{err: errWrite}
+
+	err = fact.Factorize(context.Background(), generateNumbers(1000), writer)
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errWrite)
+
+	require.Equal(t, int64(1), writer.calls.Load(), "buffer must not be flushed again after a writer error")
+}
+
+func TestBufferedWriterCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithBufferedWriter(1<<20),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	writer := newRecordingWriter()
+
+	err = fact.Factorize(ctx, generateNumbers(10_000_000), writer)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	calls := len(writer.Calls())
+
+	for _, line := range getFact(writer) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+	}
+
+	time.Sleep(time.Millisecond * 100)
+
+	require.Len(t, writer.Calls(), calls, "buffers must not be flushed after Factorize returns")
+}
@@ -0,0 +1,184 @@
+//go:build cache_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// cachedRun factorizes numbers, checks the output and returns the cache activity of the call.
+func cachedRun(t *testing.T, fact *factorizerImpl, numbers []int) CacheStats {
+	t.Helper()
+
+	before := fact.CacheStats()
+
+	writer := newWriter()
+	err := fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	allNums := make([]int, 0, len(numbers))
+	for _, line := range getFact(writer) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		allNums = append(allNums, num)
+	}
+
+	slices.Sort(allNums)
+	require.Equal(t, slices.Sorted(slices.Values(numbers)), allNums)
+
+	after := fact.CacheStats()
+
+	return CacheStats{Hits: after.Hits - before.Hits, Misses: after.Misses - before.Misses}
+}
+
+// newCachedFactorizer uses a single factorization worker, so that hits and misses are deterministic.
+func newCachedFactorizer(t *testing.T, size int) *factorizerImpl {
+	t.Helper()
+
+	fact, err := New(
+		WithFactorizationWorkers(1),
+		WithWriteWorkers(1),
+		WithCache(size),
+	)
+	require.NoError(t, err)
+
+	return fact
+}
+
+func TestCacheInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name string
+		size int
+		want string
+	}{
+		{name: "zero", size: 0, want: "0"},
+		{name: "negative", size: -1, want: "-1"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(WithCache(tt.size))
+
+			require.ErrorContains(t, err, "cache")
+			require.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestCacheAcrossCalls(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newCachedFactorizer(t, 100)
+	numbers := generateNumbers(20)
+
+	require.Equal(t, CacheStats{}, fact.CacheStats())
+	require.Equal(t, CacheStats{Misses: 20}, cachedRun(t, fact, numbers))
+
+	// the second call is served from the cache
+	require.Equal(t, CacheStats{Hits: 20}, cachedRun(t, fact, numbers))
+	require.Equal(t, CacheStats{Hits: 20, Misses: 20}, fact.CacheStats())
+}
+
+func TestCacheRepeatedWithinCall(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newCachedFactorizer(t, 100)
+
+	require.Equal(t, CacheStats{Hits: 3, Misses: 2}, cachedRun(t, fact, []int{4, 4, 9, 4, 9}))
+}
+
+func TestCacheEviction(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const size = 10
+
+	fact := newCachedFactorizer(t, size)
+
+	numbers := generateNumbers(2 * size)
+	first, second := numbers[:size], numbers[size:]
+
+	require.Equal(t, CacheStats{Misses: size}, cachedRun(t, fact, first))
+	require.Equal(t, CacheStats{Misses: size}, cachedRun(t, fact, second))
+
+	// the second batch filled the cache, so the first one was evicted
+	require.Equal(t, CacheStats{Misses: size}, cachedRun(t, fact, first))
+
+	// recomputing the first batch cached it again
+	require.Equal(t, CacheStats{Hits: size}, cachedRun(t, fact, first))
+}
+
+func TestCacheRecentlyUsedSurvives(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const size = 10
+
+	fact := newCachedFactorizer(t, size)
+
+	numbers := generateNumbers(size + 1)
+	hot := numbers[:1]
+
+	require.Equal(t, CacheStats{Misses: size}, cachedRun(t, fact, numbers[:size]))
+
+	// touching the oldest entry makes it the most recently used one
+	require.Equal(t, CacheStats{Hits: 1}, cachedRun(t, fact, hot))
+	require.Equal(t, CacheStats{Misses: 1}, cachedRun(t, fact, numbers[size:]))
+
+	require.Equal(t, CacheStats{Hits: 1}, cachedRun(t, fact, hot))
+
+	// the least recently used entry made room instead
+	require.Equal(t, CacheStats{Misses: 1}, cachedRun(t, fact, numbers[1:2]))
+}
+
+func TestCacheConcurrentCalls(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const calls = 8
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(4),
+		WithCache(500),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(1000)
+
+	writers := make([]*concurrentWriter, calls)
+	errs := make([]error, calls)
+
+	wg := new(sync.WaitGroup)
+	for i := range calls {
+		wg.Go(func() {
+			writers[i] = newWriter()
+			errs[i] = fact.Factorize(context.Background(), numbers, writers[i])
+		})
+	}
+
+	wg.Wait()
+
+	// checkFactorization memoizes into an unguarded map, so verify after the calls
+	for i := range calls {
+		require.NoError(t, errs[i])
+
+		allNums := make([]int, 0, len(numbers))
+		for _, line := range getFact(writers[i]) {
+			num, res := parseLine(t, line)
+			require.True(t, checkFactorization(num, res))
+			allNums = append(allNums, num)
+		}
+
+		slices.Sort(allNums)
+		require.Equal(t, numbers, allNums)
+	}
+
+	stats := fact.CacheStats()
+	require.Equal(t, calls*len(numbers), stats.Hits+stats.Misses)
+	require.GreaterOrEqual(t, stats.Misses, len(numbers)-500)
+}
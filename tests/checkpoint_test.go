@@ -0,0 +1,171 @@
+//go:build checkpoint_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newCheckpointFactorizer(t *testing.T, path string) *factorizerImpl {
+	t.Helper()
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(4),
+		WithCheckpointFile(path),
+	)
+	require.NoError(t, err)
+
+	return fact
+}
+
+func TestCheckpointInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithCheckpointFile(""))
+
+	require.ErrorContains(t, err, "checkpoint")
+}
+
+func TestCheckpointResumeAfterCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	path := filepath.Join(t.TempDir(), "fact.checkpoint")
+	numbers := generateNumbers(1000)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	first := newSleepWriter(time.Millisecond)
+	err := newCheckpointFactorizer(t, path).Factorize(ctx, numbers, first)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	done := len(getFact(first))
+	require.Positive(t, done)
+	require.Less(t, done, len(numbers))
+
+	// a new Factorizer stands in for a restarted process
+	second := newWriter()
+	err = newCheckpointFactorizer(t, path).Factorize(context.Background(), numbers, second)
+	require.NoError(t, err)
+
+	require.Len(t, getFact(second), len(numbers)-done, "completed numbers must be skipped")
+
+	allNums := make([]int, 0, len(numbers))
+	for _, line := range append(getFact(first), getFact(second)...) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		allNums = append(allNums, num)
+	}
+
+	slices.Sort(allNums)
+	require.Equal(t, numbers, allNums, "every number must be written exactly once across runs")
+}
+
+func TestCheckpointCompletedRun(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	path := filepath.Join(t.TempDir(), "fact.checkpoint")
+	fact := newCheckpointFactorizer(t, path)
+
+	numbers := generateNumbers(1000)
+
+	writer := newWriter()
+	err := fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+	require.Len(t, getFact(writer), len(numbers))
+
+	writer = newWriter()
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+	require.Empty(t, getFact(writer), "a completed run must not be repeated")
+}
+
+func TestCheckpointRepeatedInputs(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	path := filepath.Join(t.TempDir(), "fact.checkpoint")
+	numbers := []int{4, 4, 4, 4}
+
+	writer := newWriter()
+	err := newCheckpointFactorizer(t, path).Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	// checkpoints track input indices, so every occurrence is written
+	require.Equal(t, []string{"4 = 2 * 2", "4 = 2 * 2", "4 = 2 * 2", "4 = 2 * 2"}, getFact(writer))
+}
+
+func TestCheckpointWriterError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	path := filepath.Join(t.TempDir(), "fact.checkpoint")
+	numbers := generateNumbers(100)
+	errWrite := errors.New("disk full")
+
+	err := newCheckpointFactorizer(t, path).Factorize(context.Background(), numbers, newSleepErrorWriter(0, errWrite))
+	require.ErrorIs(t, err, ErrWriterInteraction)
+
+	// nothing reached the writer, so nothing may be marked as done
+	writer := newWriter()
+	err = newCheckpointFactorizer(t, path).Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+	require.Len(t, getFact(writer), len(numbers))
+}
+
+func TestCheckpointUnwritablePath(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	path := filepath.Join(t.TempDir(), "missing", "fact.checkpoint")
+
+	writer := newWriter()
+	err := newCheckpointFactorizer(t, path).Factorize(context.Background(), generateNumbers(100), writer)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrWriterInteraction)
+
+	require.Zero(t, len(writer.String()))
+}
+
+func TestCheckpointInputMismatch(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := generateNumbers(100)
+
+	testCases := []struct {
+		name    string
+		numbers []int
+	}{
+		{name: "different values", numbers: generateNumbers(101)[1:]},
+		{name: "shorter", numbers: generateNumbers(50)},
+		{name: "longer", numbers: generateNumbers(200)},
+		{name: "reordered", numbers: slices.Concat(numbers[50:], numbers[:50])},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "fact.checkpoint")
+
+			err := newCheckpointFactorizer(t, path).Factorize(context.Background(), numbers, newWriter())
+			require.NoError(t, err)
+
+			// the checkpoint stores a fingerprint of its input, so indices of another input are not trusted
+			writer := newWriter()
+			err = newCheckpointFactorizer(t, path).Factorize(context.Background(), tt.numbers, writer)
+			require.ErrorIs(t, err, ErrCheckpointMismatch)
+			require.NotErrorIs(t, err, ErrWriterInteraction)
+			require.Empty(t, writer.String())
+
+			// a rejected input leaves the checkpoint of the original one intact
+			writer = newWriter()
+			err = newCheckpointFactorizer(t, path).Factorize(context.Background(), numbers, writer)
+			require.NoError(t, err)
+			require.Empty(t, getFact(writer))
+		})
+	}
+}
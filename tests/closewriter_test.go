@@ -0,0 +1,166 @@
+//go:build closewriter_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type closingWriter struct {
+	sb          *strings.Builder
+	mx          *sync.Mutex
+	closed      int
+	writesAfter int
+	closeErr    error
+	sleepTime   time.Duration
+}
+
+func newClosingWriter(sleepTime time.Duration, closeErr error) *closingWriter {
+	return &closingWriter{
+		sb:        new(strings.Builder),
+		mx:        new(sync.Mutex),
+		closeErr:  closeErr,
+		sleepTime: sleepTime,
+	}
+}
+
+func (c *closingWriter) Write(p []byte) (n int, err error) {
+	time.Sleep(c.sleepTime)
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if c.closed > 0 {
+		c.writesAfter++
+	}
+
+	return c.sb.Write(p)
+}
+
+func (c *closingWriter) Close() error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.closed++
+
+	return c.closeErr
+}
+
+func (c *closingWriter) String() string {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return c.sb.String()
+}
+
+func (c *closingWriter) state() (closed, writesAfter int) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return c.closed, c.writesAfter
+}
+
+func newCloseWriterFactorizer(t *testing.T, factWorkers, writeWorkers int) *factorizerImpl {
+	t.Helper()
+
+	fact, err := New(
+		WithFactorizationWorkers(factWorkers),
+		WithWriteWorkers(writeWorkers),
+		WithCloseWriter(),
+	)
+	require.NoError(t, err)
+
+	return fact
+}
+
+func TestCloseWriterOnCompletion(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	for range 20 {
+		writer := newClosingWriter(0, nil)
+
+		err := newCloseWriterFactorizer(t, 10, 10).Factorize(context.Background(), generateNumbers(1000), writer)
+		require.NoError(t, err)
+
+		closed, writesAfter := writer.state()
+		require.Equal(t, 1, closed, "writer must be closed exactly once")
+		require.Zero(t, writesAfter, "writer must be closed after the last write")
+		require.Len(t, getFact(writer), 1000)
+	}
+}
+
+func TestCloseWriterDisabledByDefault(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	writer := newClosingWriter(0, nil)
+
+	err := newFactorizer(t, 2, 2).Factorize(context.Background(), generateNumbers(100), writer)
+	require.NoError(t, err)
+
+	closed, _ := writer.state()
+	require.Zero(t, closed)
+}
+
+func TestCloseWriterNotCloser(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	writer := newWriter()
+
+	err := newCloseWriterFactorizer(t, 2, 2).Factorize(context.Background(), []int{100, -17}, writer)
+	require.NoError(t, err)
+
+	require.Len(t, getFact(writer), 2)
+}
+
+func TestCloseWriterCancelBeforeWrites(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	writer := newClosingWriter(0, nil)
+
+	err := newCloseWriterFactorizer(t, 2, 2).Factorize(ctx, generateNumbers(100), writer)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	closed, _ := writer.state()
+	require.Zero(t, closed, "a cancelled run must leave the writer open")
+	require.Empty(t, writer.String())
+}
+
+func TestCloseWriterCancelDuringWrites(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	t.Cleanup(cancel)
+
+	writer := newClosingWriter(time.Millisecond*10, nil)
+
+	err := newCloseWriterFactorizer(t, 2, 2).Factorize(ctx, generateNumbers(1000), writer)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	closed, _ := writer.state()
+	require.Zero(t, closed, "an incomplete run must leave the writer open")
+}
+
+func TestCloseWriterCloseError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	errClose := errors.New("flush failed")
+	writer := newClosingWriter(0, errClose)
+
+	err := newCloseWriterFactorizer(t, 2, 2).Factorize(context.Background(), generateNumbers(100), writer)
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errClose)
+
+	closed, _ := writer.state()
+	require.Equal(t, 1, closed)
+	require.Len(t, getFact(writer), 100)
+}
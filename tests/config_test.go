@@ -0,0 +1,188 @@
+//go:build config_test
+
+package fact
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigInvalid(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name string
+		cfg  Config
+		want []string
+	}{
+		{name: "fact workers", cfg: Config{FactWorkers: -1}, want: []string{"factorization", "-1"}},
+		{name: "write workers", cfg: Config{WriteWorkers: -1}, want: []string{"write", "-1"}},
+		{name: "queue depth", cfg: Config{QueueDepth: -5}, want: []string{"queue", "-5"}},
+		{name: "format", cfg: Config{Format: "xml"}, want: []string{"format", "xml"}},
+		{
+			name: "all violations",
+			cfg:  Config{FactWorkers: -2, WriteWorkers: -3, Format: "xml"},
+			want: []string{"factorization", "-2", "write", "-3", "format", "xml"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			fact, err := NewFromConfig(tt.cfg)
+			require.Nil(t, fact)
+
+			for _, want := range tt.want {
+				require.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
+
+func TestConfigGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := []int{100, -17, 25, 38}
+
+	testCases := []struct {
+		name string
+		json string
+		cfg  Config
+		want []string
+	}{
+		{
+			name: "defaults",
+			json: `{}`,
+			cfg:  Config{},
+			want: []string{"100 = 2 * 2 * 5 * 5", "-17 = -1 * 17", "25 = 5 * 5", "38 = 2 * 19"},
+		},
+		{
+			name: "workers",
+			json: `{"fact_workers": 3, "write_workers": 2, "queue_depth": 16}`,
+			cfg:  Config{FactWorkers: 3, WriteWorkers: 2, QueueDepth: 16},
+			want: []string{"100 = 2 * 2 * 5 * 5", "-17 = -1 * 17", "25 = 5 * 5", "38 = 2 * 19"},
+		},
+		{
+			name: "text",
+			json: `{"format": "text"}`,
+			cfg:  Config{Format: "text"},
+			want: []string{"100 = 2 * 2 * 5 * 5", "-17 = -1 * 17", "25 = 5 * 5", "38 = 2 * 19"},
+		},
+		{
+			name: "csv",
+			json: `{"fact_workers": 2, "format": "csv"}`,
+			cfg:  Config{FactWorkers: 2, Format: "csv"},
+			want: []string{"100,2;2;5;5", "-17,-1;17", "25,5;5", "38,2;19"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg Config
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &cfg))
+			require.Equal(t, tt.cfg, cfg)
+
+			fact, err := NewFromConfig(cfg)
+			require.NoError(t, err)
+
+			writer := newWriter()
+			err = fact.Factorize(context.Background(), numbers, writer)
+			require.NoError(t, err)
+
+			facts := getFact(writer)
+			slices.Sort(facts)
+			slices.Sort(tt.want)
+
+			require.Equal(t, tt.want, facts)
+		})
+	}
+}
+
+func TestConfigWorkers(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const (
+		factWorkers  = 7
+		writeWorkers = 13
+	)
+
+	var cfg Config
+	require.NoError(t, json.Unmarshal([]byte(`{"fact_workers": 7, "write_workers": 13}`), &cfg))
+
+	fact, err := NewFromConfig(cfg)
+	require.NoError(t, err)
+
+	// slow writes keep both pools busy, so every configured worker is observed
+	got := inspectNumGoroutines(t, func() {
+		err = fact.Factorize(context.Background(), generateNumbers(2*(factWorkers+writeWorkers)),
+			newSleepWriter(time.Millisecond*200))
+	})
+
+	require.NoError(t, err)
+	require.InDelta(t, factWorkers+writeWorkers, got, 3)
+}
+
+func TestConfigFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fact.json")
+
+	err := os.WriteFile(path, []byte(`{
+	"fact_workers": 4,
+	"write_workers": 2,
+	"queue_depth": 8,
+	"format": "csv"
+}`), 0o600)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var cfg Config
+	require.NoError(t, json.Unmarshal(data, &cfg))
+
+	want := Config{FactWorkers: 4, WriteWorkers: 2, QueueDepth: 8, Format: "csv"}
+	require.Equal(t, want, cfg)
+
+	// encoding uses the same keys, so a written config loads back unchanged
+	data, err = json.Marshal(cfg)
+	require.NoError(t, err)
+
+	var keys map[string]any
+	require.NoError(t, json.Unmarshal(data, &keys))
+	require.EqualValues(t, 4, keys["fact_workers"])
+	require.EqualValues(t, 2, keys["write_workers"])
+	require.EqualValues(t, 8, keys["queue_depth"])
+	require.Equal(t, "csv", keys["format"])
+
+	var got Config
+	require.NoError(t, json.Unmarshal(data, &got))
+	require.Equal(t, want, got)
+}
+
+func TestConfigCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := NewFromConfig(Config{FactWorkers: 10, WriteWorkers: 10})
+	require.NoError(t, err)
+
+	numbers := generateNumbers(10_000)
+	writer := newWriter()
+
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	allNums := make([]int, 0, len(numbers))
+	for _, line := range getFact(writer) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		allNums = append(allNums, num)
+	}
+
+	slices.Sort(allNums)
+	require.Equal(t, numbers, allNums)
+}
@@ -0,0 +1,107 @@
+//go:build cooperative_test
+
+package fact
+
+import (
+	"context"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newCooperativeFactorizer(t *testing.T, factWorkers int) *factorizerImpl {
+	t.Helper()
+
+	fact, err := New(
+		WithFactorizationWorkers(factWorkers),
+		WithWriteWorkers(1),
+		WithCooperativeFactorization(),
+	)
+	require.NoError(t, err)
+
+	return fact
+}
+
+func TestCooperativeGoldenOutput(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	numbers := []int{hardSemiprimes[3], 999999937, math.MaxInt, math.MinInt, -38, 0, 1}
+	want := []string{
+		"999999866000004473 = 999999929 * 999999937",
+		"999999937 = 999999937",
+		"9223372036854775807 = 7 * 7 * 73 * 127 * 337 * 92737 * 649657",
+		minIntLine(),
+		"-38 = -1 * 2 * 19",
+		"0 = 0",
+		"1 = 1",
+	}
+
+	writer := newWriter()
+	err := newCooperativeFactorizer(t, 4).Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	facts := getFact(writer)
+	slices.Sort(facts)
+	slices.Sort(want)
+
+	require.Equal(t, want, facts)
+}
+
+func TestCooperativeSiblingsStop(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	const factWorkers = 8
+
+	fact := newCooperativeFactorizer(t, factWorkers)
+
+	// the first sibling to find a factor cancels the others, leaving no goroutine behind
+	for range 20 {
+		for _, n := range hardSemiprimes {
+			writer := newWriter()
+			err := fact.Factorize(context.Background(), []int{n}, writer)
+			require.NoError(t, err)
+
+			lines := getFact(writer)
+			require.Len(t, lines, 1)
+
+			num, res := parseLine(t, lines[0])
+			require.Equal(t, n, num)
+			require.Len(t, res, 2)
+			require.True(t, checkFactorization(num, res))
+		}
+	}
+}
+
+func TestCooperativeCancel(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	numbers := slices.Repeat([]int{hardSemiprimes[3]}, 8)
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(1),
+		WithCooperativeFactorization(),
+		WithFaultInjector(stallOn(hardSemiprimes[3])),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	start := time.Now()
+
+	writer := newWriter()
+
+	err = fact.Factorize(ctx, numbers, writer)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+	require.Empty(t, getFact(writer))
+
+	// every sibling of a cooperative search stops on cancellation
+	require.Less(t, time.Since(start), time.Millisecond*500)
+}
@@ -0,0 +1,86 @@
+//go:build cpudefaults_test
+
+package fact
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultsFromCPUInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name        string
+		factPerCPU  float64
+		writePerCPU float64
+		want        string
+	}{
+		{name: "zero fact", factPerCPU: 0, writePerCPU: 1, want: "0"},
+		{name: "negative fact", factPerCPU: -1.5, writePerCPU: 1, want: "-1.5"},
+		{name: "negative write", factPerCPU: 1, writePerCPU: -2, want: "-2"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(WithDefaultsFromCPU(tt.factPerCPU, tt.writePerCPU))
+
+			require.ErrorContains(t, err, "CPU")
+			require.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestDefaultsFromCPUScaling(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		procs       int
+		factPerCPU  float64
+		writePerCPU float64
+		want        int
+	}{
+		{procs: 2, factPerCPU: 1, writePerCPU: 16, want: 2 + 32},
+		{procs: 8, factPerCPU: 2, writePerCPU: 16, want: 16 + 128},
+		{procs: 4, factPerCPU: 0.5, writePerCPU: 8.5, want: 2 + 34},
+		// fractional products never drop below one worker per pool
+		{procs: 2, factPerCPU: 0.1, writePerCPU: 0.1, want: 1 + 1},
+	}
+
+	for _, tt := range testCases {
+		t.Run("procs="+strconv.Itoa(tt.procs), func(t *testing.T) {
+			// GOMAXPROCS is the limit a cgroup-aware runtime reports, unlike NumCPU
+			setGOMAXPROCS(t, tt.procs)
+
+			fact, err := New(WithDefaultsFromCPU(tt.factPerCPU, tt.writePerCPU))
+			require.NoError(t, err)
+
+			require.InDelta(t, tt.want, countWorkers(t, fact, tt.want), 10)
+		})
+	}
+}
+
+func TestDefaultsFromCPUOverriddenByOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	setGOMAXPROCS(t, 4)
+
+	fact, err := New(
+		WithDefaultsFromCPU(1, 32),
+		WithWriteWorkers(10),
+	)
+	require.NoError(t, err)
+
+	// explicit worker counts win regardless of option order
+	require.InDelta(t, 4+10, countWorkers(t, fact, 4+10), 5)
+
+	fact, err = New(
+		WithWriteWorkers(10),
+		WithDefaultsFromCPU(1, 32),
+	)
+	require.NoError(t, err)
+
+	require.InDelta(t, 4+10, countWorkers(t, fact, 4+10), 5)
+}
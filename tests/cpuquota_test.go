@@ -0,0 +1,27 @@
+//go:build cpuquota_test
+
+package fact
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultsFollowGOMAXPROCS(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	if runtime.NumCPU() < 4 {
+		t.Skip("requires more CPUs than the emulated quota")
+	}
+
+	// since Go 1.25 the runtime lowers GOMAXPROCS to the cgroup CPU quota,
+	// so New must size its pools from GOMAXPROCS rather than NumCPU
+	setGOMAXPROCS(t, 2)
+
+	fact, err := New()
+	require.NoError(t, err)
+
+	require.InDelta(t, 2+2, countWorkers(t, fact, 2+2), 3)
+}
@@ -0,0 +1,167 @@
+//go:build csv_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const csvHeader = "n,factors"
+
+func parseCSVLine(t *testing.T, line string) (int, []int) {
+	t.Helper()
+
+	s := strings.Split(line, ",")
+	require.Len(t, s, 2, "malformed csv row %q", line)
+
+	return strToInt(t, s[0]), delimiterStringsToSliceInt(t, strings.Split(s[1], ";"))
+}
+
+func TestCSVGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name    string
+		numbers []int
+		header  bool
+		want    []string
+	}{
+		{
+			name:    "no header",
+			numbers: []int{0, 1, 100, -17, 25, 38},
+			header:  false,
+			want: []string{
+				"0,0",
+				"1,1",
+				"100,2;2;5;5",
+				"-17,-1;17",
+				"25,5;5",
+				"38,2;19",
+			},
+		},
+		{
+			name:    "header",
+			numbers: []int{10, 4, 4, -20},
+			header:  true,
+			want: []string{
+				"10,2;5",
+				"4,2;2",
+				"4,2;2",
+				"-20,-1;2;2;5",
+			},
+		},
+		{
+			name:    "empty with header",
+			numbers: []int{},
+			header:  true,
+			want:    []string{},
+		},
+		{
+			name:    "empty without header",
+			numbers: []int{},
+			header:  false,
+			want:    []string{},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			fact, err := New(
+				WithFactorizationWorkers(3),
+				WithWriteWorkers(3),
+				WithCSVOutput(tt.header),
+			)
+			require.NoError(t, err)
+
+			writer := newWriter()
+			err = fact.Factorize(context.Background(), tt.numbers, writer)
+			require.NoError(t, err)
+
+			rows := getFact(writer)
+			if tt.header {
+				require.NotEmpty(t, rows)
+				require.Equal(t, csvHeader, rows[0])
+				rows = rows[1:]
+			}
+
+			slices.Sort(tt.want)
+			slices.Sort(rows)
+
+			require.Equal(t, tt.want, rows)
+		})
+	}
+}
+
+func TestCSVHeaderWrittenOnce(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(100),
+		WithWriteWorkers(1000),
+		WithCSVOutput(true),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(10_000)
+	writer := newWriter()
+
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	rows := getFact(writer)
+	require.Len(t, rows, len(numbers)+1)
+	require.Equal(t, csvHeader, rows[0])
+
+	allNums := make([]int, 0, len(numbers))
+	for _, row := range rows[1:] {
+		require.NotEqual(t, csvHeader, row)
+
+		num, res := parseCSVLine(t, row)
+		require.True(t, checkFactorization(num, res))
+		allNums = append(allNums, num)
+	}
+
+	slices.Sort(allNums)
+	require.Equal(t, numbers, allNums)
+}
+
+func TestCSVCancelledWritesNothing(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(WithCSVOutput(true))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	writer := newWriter()
+	err = fact.Factorize(ctx, generateNumbers(1000), writer)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	require.Zero(t, len(writer.String()))
+}
+
+func TestCSVHeaderWriterError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(10),
+		WithCSVOutput(true),
+	)
+	require.NoError(t, err)
+
+	errHeader := errors.New("header write failed")
+	writer := newSleepErrorWriter(time.Millisecond, errHeader)
+
+	err = fact.Factorize(context.Background(), generateNumbers(1000), writer)
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errHeader)
+}
@@ -0,0 +1,118 @@
+//go:build ctxwriter_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCtxKey struct{}
+
+// contextWriter records what it observes through WriteContext and fails on plain Write.
+type contextWriter struct {
+	*concurrentWriter
+	block    bool
+	values   atomic.Int64
+	deadline atomic.Bool
+	plain    atomic.Int64
+}
+
+func (c *contextWriter) Write(p []byte) (n int, err error) {
+	c.plain.Add(1)
+
+	return c.concurrentWriter.Write(p)
+}
+
+func (c *contextWriter) WriteContext(ctx context.Context, p []byte) (n int, err error) {
+	if ctx.Value(testCtxKey{}) == "trace-id" {
+		c.values.Add(1)
+	}
+
+	if _, ok := ctx.Deadline(); ok {
+		c.deadline.Store(true)
+	}
+
+	if c.block {
+		<-ctx.Done()
+
+		return 0, ctx.Err()
+	}
+
+	return c.concurrentWriter.Write(p)
+}
+
+type failingContextWriter struct {
+	err error
+}
+
+func (f *failingContextWriter) Write(_ []byte) (n int, err error) {
+	return 0, errors.New("plain write used")
+}
+
+func (f *failingContextWriter) WriteContext(_ context.Context, _ []byte) (n int, err error) {
+	return 0, f.err
+}
+
+func TestContextWriterReceivesValues(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	ctx := context.WithValue(context.Background(), testCtxKey{}, "trace-id")
+	writer := &contextWriter{concurrentWriter: newWriter()}
+
+	numbers := generateNumbers(1000)
+
+	err := newFactorizer(t, 4, 4).Factorize(ctx, numbers, writer)
+	require.NoError(t, err)
+
+	require.Len(t, getFact(writer), len(numbers))
+	require.Equal(t, int64(len(numbers)), writer.values.Load())
+	require.Zero(t, writer.plain.Load(), "Write must not be used when WriteContext is available")
+	require.False(t, writer.deadline.Load())
+}
+
+func TestContextWriterReceivesDeadline(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(cancel)
+
+	writer := &contextWriter{concurrentWriter: newWriter()}
+
+	err := newFactorizer(t, 2, 2).Factorize(ctx, generateNumbers(10), writer)
+	require.NoError(t, err)
+
+	require.True(t, writer.deadline.Load())
+}
+
+func TestContextWriterCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	writer := &contextWriter{concurrentWriter: newWriter(), block: true}
+
+	start := time.Now()
+
+	// a blocked WriteContext is released by the per-call context
+	err := newFactorizer(t, 2, 2).Factorize(ctx, generateNumbers(1000), writer)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestContextWriterError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	errWrite := errors.New("disk full")
+	writer := &failingContextWriter{err: errWrite}
+
+	err := newFactorizer(t, 2, 2).Factorize(context.Background(), generateNumbers(1000), writer)
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errWrite)
+}
@@ -0,0 +1,189 @@
+//go:build dedup_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func repeatNumber(n, count int) []int {
+	res := make([]int, count)
+	for i := range res {
+		res[i] = n
+	}
+
+	return res
+}
+
+func TestDeduplicationGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name    string
+		numbers []int
+		want    []string
+	}{
+		{
+			name:    "repeated inputs",
+			numbers: []int{4, 4},
+			want: []string{
+				"4 = 2 * 2",
+				"4 = 2 * 2",
+			},
+		},
+		{
+			name:    "mixed",
+			numbers: []int{100, -17, 100, 25, -17, 100},
+			want: []string{
+				"-17 = -1 * 17",
+				"-17 = -1 * 17",
+				"100 = 2 * 2 * 5 * 5",
+				"100 = 2 * 2 * 5 * 5",
+				"100 = 2 * 2 * 5 * 5",
+				"25 = 5 * 5",
+			},
+		},
+		{
+			name:    "sign matters",
+			numbers: []int{6, -6, 6, -6},
+			want: []string{
+				"-6 = -1 * 2 * 3",
+				"-6 = -1 * 2 * 3",
+				"6 = 2 * 3",
+				"6 = 2 * 3",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			fact, err := New(
+				WithFactorizationWorkers(3),
+				WithWriteWorkers(3),
+				WithDeduplication(),
+			)
+			require.NoError(t, err)
+
+			writer := newWriter()
+			err = fact.Factorize(context.Background(), tt.numbers, writer)
+			require.NoError(t, err)
+
+			facts := getFact(writer)
+			slices.Sort(facts)
+			slices.Sort(tt.want)
+
+			require.Equal(t, tt.want, facts)
+		})
+	}
+}
+
+func TestDeduplicationCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(10),
+		WithDeduplication(),
+	)
+	require.NoError(t, err)
+
+	numbers := make([]int, 0, 100_000)
+	for i := range 100_000 {
+		numbers = append(numbers, i%1000-500)
+	}
+
+	writer := newWriter()
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	allNums := make([]int, 0, len(numbers))
+	for _, line := range getFact(writer) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		allNums = append(allNums, num)
+	}
+
+	slices.Sort(allNums)
+	slices.Sort(numbers)
+	require.Equal(t, numbers, allNums)
+}
+
+func TestDeduplicationFactorizesOnce(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name       string
+		numbers    []int
+		factorized int
+		reused     int
+	}{
+		{name: "repeated inputs", numbers: []int{4, 4}, factorized: 1, reused: 1},
+		{name: "mixed", numbers: []int{100, -17, 100, 25, -17, 100}, factorized: 3, reused: 3},
+		{name: "sign matters", numbers: []int{6, -6, 6, -6}, factorized: 2, reused: 2},
+		{name: "thousand copies", numbers: repeatNumber(1000003, 1000), factorized: 1, reused: 999},
+		{name: "distinct", numbers: generateNumbers(100), factorized: 100, reused: 0},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			fact, err := New(
+				WithFactorizationWorkers(10),
+				WithWriteWorkers(10),
+				WithDeduplication(),
+			)
+			require.NoError(t, err)
+
+			writer := newWriter()
+			err = fact.Factorize(context.Background(), tt.numbers, writer)
+			require.NoError(t, err)
+			require.Len(t, getFact(writer), len(tt.numbers))
+
+			// concurrent workers share one in-flight factorization per distinct value
+			require.Equal(t, DedupStats{Factorized: tt.factorized, Reused: tt.reused}, fact.DedupStats())
+		})
+	}
+}
+
+func TestDeduplicationStatsAccumulate(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(WithDeduplication())
+	require.NoError(t, err)
+
+	require.Zero(t, fact.DedupStats())
+
+	// deduplication is per call: the second call factorizes 4 again
+	for range 2 {
+		require.NoError(t, fact.Factorize(context.Background(), []int{4, 4, 4}, newWriter()))
+	}
+
+	require.Equal(t, DedupStats{Factorized: 2, Reused: 4}, fact.DedupStats())
+}
+
+func TestDeduplicationCancel(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(1),
+		WithDeduplication(),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	start := time.Now()
+
+	// workers waiting on a shared in-flight factorization must still observe cancellation
+	err = fact.Factorize(ctx, repeatNumber(9223372036854775783, 1_000_000), newSleepWriter(time.Millisecond))
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	require.Less(t, time.Since(start), time.Second)
+}
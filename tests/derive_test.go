@@ -0,0 +1,123 @@
+//go:build derive_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// sharedCache counts hits so tests can tell whether derived instances share it.
+type sharedCache struct {
+	mx   sync.Mutex
+	m    map[int][]int
+	hits int
+}
+
+func (c *sharedCache) Get(n int) ([]int, bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	f, ok := c.m[n]
+	if ok {
+		c.hits++
+	}
+
+	return slices.Clone(f), ok
+}
+
+func (c *sharedCache) Put(n int, f []int) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.m[n] = slices.Clone(f)
+}
+
+func TestDeriveInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	derived, err := newFactorizer(t, 2, 2).With(WithFactorizationWorkers(-1))
+
+	require.ErrorContains(t, err, "factorization")
+	require.ErrorContains(t, err, "-1")
+	require.Nil(t, derived)
+}
+
+func TestDeriveOverridesWorkers(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const override = 100
+
+	base := newFactorizer(t, 2, 2)
+
+	derived, err := base.With(WithWriteWorkers(override))
+	require.NoError(t, err)
+
+	count := func(f Factorizer, numbers int) int {
+		return inspectNumGoroutines(t, func() {
+			err := f.Factorize(context.Background(), generateNumbers(numbers), newSleepWriter(time.Millisecond*200))
+			require.NoError(t, err)
+		})
+	}
+
+	require.InDelta(t, 2+override, count(derived, 2*override), 20)
+
+	// the original keeps its own configuration
+	require.InDelta(t, 2+2, count(base, 10), 3)
+}
+
+func TestDeriveOverridesFormat(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	base := newFactorizer(t, 2, 2)
+
+	derived, err := base.With(WithCSVOutput(false))
+	require.NoError(t, err)
+
+	numbers := []int{100, -17}
+
+	csv := newWriter()
+	require.NoError(t, derived.Factorize(context.Background(), numbers, csv))
+
+	text := newWriter()
+	require.NoError(t, base.Factorize(context.Background(), numbers, text))
+
+	csvLines, textLines := getFact(csv), getFact(text)
+	slices.Sort(csvLines)
+	slices.Sort(textLines)
+
+	require.Equal(t, []string{"-17,-1;17", "100,2;2;5;5"}, csvLines)
+	require.Equal(t, []string{"-17 = -1 * 17", "100 = 2 * 2 * 5 * 5"}, textLines)
+}
+
+func TestDeriveSharesCache(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	cache := &sharedCache{m: make(map[int][]int)}
+
+	base, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithFactorCache(cache),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(1000)
+
+	require.NoError(t, base.Factorize(context.Background(), numbers, newWriter()))
+
+	derived, err := base.With(WithFactorizationWorkers(8), WithCSVOutput(false))
+	require.NoError(t, err)
+
+	writer := newWriter()
+	require.NoError(t, derived.Factorize(context.Background(), numbers, writer))
+
+	// every result of the derived instance is served from the base cache
+	require.Equal(t, len(numbers), cache.hits)
+	require.Len(t, getFact(writer), len(numbers))
+}
@@ -0,0 +1,144 @@
+//go:build divisorfuncs_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func bruteDivisorFuncs(n int) (int, int) {
+	n = max(n, -n)
+
+	count, sum := 0, 0
+	for d := 1; d <= n; d++ {
+		if n%d == 0 {
+			count++
+			sum += d
+		}
+	}
+
+	return count, sum
+}
+
+func TestDivisorFuncsGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := []int{1, 12, -12, 97, 100, 1024}
+
+	testCases := []struct {
+		name  string
+		batch func(*factorizerImpl, TestWriter) error
+		want  []string
+	}{
+		{
+			name: "count",
+			batch: func(f *factorizerImpl, w TestWriter) error {
+				return f.NumDivisors(context.Background(), numbers, w)
+			},
+			want: []string{"1: 1", "12: 6", "-12: 6", "97: 2", "100: 9", "1024: 11"},
+		},
+		{
+			name: "sum",
+			batch: func(f *factorizerImpl, w TestWriter) error {
+				return f.SigmaDivisors(context.Background(), numbers, w)
+			},
+			want: []string{"1: 1", "12: 28", "-12: 28", "97: 98", "100: 217", "1024: 2047"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := newWriter()
+			err := tt.batch(newFactorizer(t, 2, 2), writer)
+			require.NoError(t, err)
+
+			lines := getFact(writer)
+			slices.Sort(lines)
+			slices.Sort(tt.want)
+
+			require.Equal(t, tt.want, lines)
+		})
+	}
+}
+
+func TestDivisorFuncsCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := generateNumbers(5000)[1:]
+	fact := newFactorizer(t, 8, 8)
+
+	counts := newWriter()
+	err := fact.NumDivisors(context.Background(), numbers, counts)
+	require.NoError(t, err)
+
+	sums := newWriter()
+	err = fact.SigmaDivisors(context.Background(), numbers, sums)
+	require.NoError(t, err)
+
+	require.Len(t, getFact(counts), len(numbers))
+	require.Len(t, getFact(sums), len(numbers))
+
+	for _, line := range getFact(counts) {
+		n, got := parseValueLine(t, line)
+		count, _ := bruteDivisorFuncs(n)
+		require.Equal(t, strconv.Itoa(count), got, "line %q", line)
+	}
+
+	for _, line := range getFact(sums) {
+		n, got := parseValueLine(t, line)
+		_, sum := bruteDivisorFuncs(n)
+		require.Equal(t, strconv.Itoa(sum), got, "line %q", line)
+	}
+}
+
+func TestDivisorFuncsLarge(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 1, 1)
+
+	// 7^2 * 73 * 127 * 337 * 92737 * 649657
+	writer := newWriter()
+	err := fact.NumDivisors(context.Background(), []int{math.MaxInt}, writer)
+	require.NoError(t, err)
+	require.Equal(t, []string{"9223372036854775807: 96"}, getFact(writer))
+
+	// the divisor sum of MaxInt does not fit into int
+	err = fact.SigmaDivisors(context.Background(), []int{math.MaxInt}, newWriter())
+	require.ErrorContains(t, err, "overflow")
+	require.NotErrorIs(t, err, ErrWriterInteraction)
+}
+
+func TestDivisorFuncsErrors(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	t.Run("zero", func(t *testing.T) {
+		err := newFactorizer(t, 2, 2).NumDivisors(context.Background(), []int{4, 0, 9}, newWriter())
+		require.ErrorContains(t, err, "0")
+		require.NotErrorIs(t, err, ErrWriterInteraction)
+	})
+
+	t.Run("writer", func(t *testing.T) {
+		errWrite := errors.New("sink closed")
+
+		err := newFactorizer(t, 2, 2).SigmaDivisors(context.Background(), generateNumbers(1000)[1:], newSleepErrorWriter(time.Millisecond, errWrite))
+		require.ErrorIs(t, err, ErrWriterInteraction)
+		require.ErrorIs(t, err, errWrite)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+		t.Cleanup(cancel)
+
+		err := newFactorizer(t, 2, 2).NumDivisors(ctx, generateNumbers(1_000_000)[1:], newSleepWriter(time.Millisecond))
+		require.ErrorIs(t, err, ErrFactorizationCancelled)
+	})
+}
@@ -0,0 +1,174 @@
+//go:build divisors_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func bruteDivisors(n int) []int {
+	n = max(n, -n)
+
+	divisors := make([]int, 0)
+	for d := 1; d <= n; d++ {
+		if n%d == 0 {
+			divisors = append(divisors, d)
+		}
+	}
+
+	return divisors
+}
+
+func parseDivisorsLine(t *testing.T, line string) (int, []int) {
+	t.Helper()
+
+	left, right, found := strings.Cut(line, ": ")
+	require.True(t, found, "malformed divisors line %q", line)
+
+	return strToInt(t, left), delimiterStringsToSliceInt(t, strings.Fields(right))
+}
+
+func TestDivisorsGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		n    int
+		want []int
+	}{
+		{n: 1, want: []int{1}},
+		{n: -1, want: []int{1}},
+		{n: 97, want: []int{1, 97}},
+		{n: 12, want: []int{1, 2, 3, 4, 6, 12}},
+		{n: -12, want: []int{1, 2, 3, 4, 6, 12}},
+		{n: 100, want: []int{1, 2, 4, 5, 10, 20, 25, 50, 100}},
+		{n: 64, want: []int{1, 2, 4, 8, 16, 32, 64}},
+	}
+
+	fact := newFactorizer(t, 2, 2)
+
+	for _, tt := range testCases {
+		t.Run(strconv.Itoa(tt.n), func(t *testing.T) {
+			got, err := fact.Divisors(context.Background(), tt.n)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDivisorsZero(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	// every integer divides zero
+	got, err := newFactorizer(t, 1, 1).Divisors(context.Background(), 0)
+	require.ErrorContains(t, err, "0")
+	require.Nil(t, got)
+}
+
+func TestDivisorsMaxInt(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	// 7^2 * 73 * 127 * 337 * 92737 * 649657
+	got, err := newFactorizer(t, 1, 1).Divisors(context.Background(), math.MaxInt)
+	require.NoError(t, err)
+
+	require.Len(t, got, 3*2*2*2*2*2)
+	require.True(t, slices.IsSorted(got))
+	require.Equal(t, 1, got[0])
+	require.Equal(t, math.MaxInt, got[len(got)-1])
+
+	for _, d := range got {
+		require.Zero(t, math.MaxInt%d, "%d does not divide MaxInt", d)
+	}
+}
+
+func TestDivisorsCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	got, err := newFactorizer(t, 1, 1).Divisors(ctx, 12)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+	require.Nil(t, got)
+}
+
+func TestDivisorsAllGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := []int{12, -17, 1, 100}
+	want := []string{
+		"12: 1 2 3 4 6 12",
+		"-17: 1 17",
+		"1: 1",
+		"100: 1 2 4 5 10 20 25 50 100",
+	}
+
+	writer := newWriter()
+	err := newFactorizer(t, 2, 2).DivisorsAll(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	slices.Sort(lines)
+	slices.Sort(want)
+
+	require.Equal(t, want, lines)
+}
+
+func TestDivisorsAllCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := make([]int, 0, 4000)
+	for i := 1; i <= 2000; i++ {
+		numbers = append(numbers, i, -i)
+	}
+
+	writer := newWriter()
+	err := newFactorizer(t, 8, 8).DivisorsAll(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	require.Len(t, lines, len(numbers))
+
+	for _, line := range lines {
+		n, divisors := parseDivisorsLine(t, line)
+		require.Equal(t, bruteDivisors(n), divisors, "line %q", line)
+	}
+}
+
+func TestDivisorsAllErrors(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	t.Run("writer", func(t *testing.T) {
+		errWrite := errors.New("sink closed")
+
+		err := newFactorizer(t, 2, 2).DivisorsAll(context.Background(), generateNumbers(1000)[1:], newSleepErrorWriter(time.Millisecond, errWrite))
+		require.ErrorIs(t, err, ErrWriterInteraction)
+		require.ErrorIs(t, err, errWrite)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+		t.Cleanup(cancel)
+
+		err := newFactorizer(t, 2, 2).DivisorsAll(ctx, generateNumbers(1_000_000)[1:], newSleepWriter(time.Millisecond))
+		require.ErrorIs(t, err, ErrFactorizationCancelled)
+	})
+
+	t.Run("zero", func(t *testing.T) {
+		err := newFactorizer(t, 2, 2).DivisorsAll(context.Background(), []int{4, 0, 9}, newWriter())
+		require.ErrorContains(t, err, "0")
+		require.NotErrorIs(t, err, ErrWriterInteraction)
+		require.NotErrorIs(t, err, ErrFactorizationCancelled)
+	})
+}
@@ -0,0 +1,142 @@
+//go:build drain_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// cancelAfter cancels ctx after d and returns the number of lines written at that moment.
+func cancelAfter(d time.Duration, cancel context.CancelFunc, writer TestWriter) <-chan int {
+	written := make(chan int, 1)
+
+	go func() {
+		time.Sleep(d)
+		written <- len(getFact(writer))
+		cancel()
+	}()
+
+	return written
+}
+
+func TestDrainOnCancelInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithDrainOnCancel(-time.Second))
+
+	require.ErrorContains(t, err, "drain")
+	require.ErrorContains(t, err, "-1s")
+}
+
+func TestDrainOnCancelFlushesInFlight(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const (
+		factWorkers  = 4
+		writeWorkers = 1
+	)
+
+	fact, err := New(
+		WithFactorizationWorkers(factWorkers),
+		WithWriteWorkers(writeWorkers),
+		WithDrainOnCancel(time.Second),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	writer := newSleepWriter(time.Millisecond * 10)
+	atCancel := cancelAfter(time.Millisecond*55, cancel, writer)
+
+	err = fact.Factorize(ctx, generateNumbers(1000), writer)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	before := <-atCancel
+	lines := getFact(writer)
+
+	// results held by blocked factorization workers must reach the writer
+	require.GreaterOrEqual(t, len(lines), before+2)
+
+	// but no new numbers may be taken after cancellation
+	require.LessOrEqual(t, len(lines), before+factWorkers+writeWorkers+1)
+
+	for _, line := range lines {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+	}
+}
+
+func TestDrainOnCancelBounded(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const writeTime = time.Millisecond * 50
+
+	fact, err := New(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(1),
+		WithDrainOnCancel(time.Millisecond*100),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	writer := newSleepWriter(writeTime)
+	atCancel := cancelAfter(time.Millisecond*20, cancel, writer)
+
+	start := time.Now()
+
+	err = fact.Factorize(ctx, generateNumbers(1000), writer)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	// draining ten results would take 500ms; the grace period cuts it short
+	require.Less(t, time.Since(start), time.Millisecond*20+time.Millisecond*100+writeTime+time.Millisecond*100)
+	require.Less(t, len(getFact(writer)), <-atCancel+10)
+}
+
+func TestDrainOnCancelZeroGrace(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(1),
+		WithDrainOnCancel(0),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	writer := newWriter()
+
+	err = fact.Factorize(ctx, generateNumbers(1000), writer)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	require.Zero(t, len(writer.String()))
+}
+
+func TestDrainOnCancelWriterError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(2),
+		WithDrainOnCancel(time.Second),
+	)
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	// a writer error is not a cancellation and is not drained
+	err = fact.Factorize(context.Background(), generateNumbers(1000), newSleepErrorWriter(time.Millisecond*10, errors.New("disk full")))
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.NotErrorIs(t, err, ErrFactorizationCancelled)
+
+	require.Less(t, time.Since(start), time.Millisecond*500)
+}
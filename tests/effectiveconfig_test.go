@@ -0,0 +1,86 @@
+//go:build effectiveconfig_test
+
+package fact
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffectiveConfigDefaults(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New()
+	require.NoError(t, err)
+
+	require.Equal(t, EffectiveConfig{
+		FactWorkers:  runtime.GOMAXPROCS(0),
+		WriteWorkers: runtime.GOMAXPROCS(0),
+		QueueDepth:   0,
+		Format:       "text",
+	}, fact.Config())
+}
+
+func TestEffectiveConfigExplicit(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	chain := []Rule{
+		{MaxBits: 20, Algorithm: AlgorithmTrialDivision},
+		{MaxBits: 64, Algorithm: AlgorithmRho},
+	}
+
+	fact, err := New(
+		WithFactorizationWorkers(3),
+		WithWriteWorkers(7),
+		WithQueueDepth(16),
+		WithCSVOutput(false),
+		WithAlgorithmChain(chain...),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, EffectiveConfig{
+		FactWorkers:  3,
+		WriteWorkers: 7,
+		QueueDepth:   16,
+		Format:       "csv",
+		Algorithms:   chain,
+	}, fact.Config())
+}
+
+func TestEffectiveConfigLastOptionWins(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(3),
+		WithFactorizationWorkers(5),
+	)
+	require.NoError(t, err)
+
+	cfg := fact.Config()
+	require.Equal(t, 5, cfg.FactWorkers)
+	require.Equal(t, runtime.GOMAXPROCS(0), cfg.WriteWorkers)
+}
+
+func TestEffectiveConfigIsCopy(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	chain := []Rule{{MaxBits: 64, Algorithm: AlgorithmRho}}
+
+	fact, err := New(WithAlgorithmChain(chain...))
+	require.NoError(t, err)
+
+	// neither the caller's slice nor the returned one aliases the internal chain
+	chain[0].Algorithm = AlgorithmTrialDivision
+
+	cfg := fact.Config()
+	require.Equal(t, AlgorithmRho, cfg.Algorithms[0].Algorithm)
+
+	cfg.Algorithms[0].MaxBits = 1
+	cfg.FactWorkers = 1000
+
+	again := fact.Config()
+	require.EqualValues(t, 64, again.Algorithms[0].MaxBits)
+	require.Equal(t, runtime.GOMAXPROCS(0), again.FactWorkers)
+}
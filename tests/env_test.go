@@ -0,0 +1,120 @@
+//go:build env_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvDefaultsInvalid(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{name: "negative fact workers", key: "FACT_WORKERS", value: "-1"},
+		{name: "malformed fact workers", key: "FACT_WORKERS", value: "four"},
+		{name: "negative write workers", key: "FACT_WRITE_WORKERS", value: "-1"},
+		{name: "unknown format", key: "FACT_FORMAT", value: "xml"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.key, tt.value)
+
+			_, err := New(WithEnvDefaults())
+
+			// the variable name tells operators which setting to fix
+			require.ErrorContains(t, err, tt.key)
+			require.ErrorContains(t, err, tt.value)
+		})
+	}
+}
+
+func TestEnvDefaultsUnset(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	for _, key := range []string{"FACT_WORKERS", "FACT_WRITE_WORKERS", "FACT_FORMAT"} {
+		t.Setenv(key, "")
+	}
+
+	fact, err := New(WithEnvDefaults())
+	require.NoError(t, err)
+
+	writer := newWriter()
+	err = fact.Factorize(context.Background(), []int{100, -17}, writer)
+	require.NoError(t, err)
+
+	facts := getFact(writer)
+	slices.Sort(facts)
+	require.Equal(t, []string{"-17 = -1 * 17", "100 = 2 * 2 * 5 * 5"}, facts)
+}
+
+func TestEnvDefaultsFormat(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	t.Setenv("FACT_FORMAT", "csv")
+
+	fact, err := New(WithEnvDefaults())
+	require.NoError(t, err)
+
+	writer := newWriter()
+	err = fact.Factorize(context.Background(), []int{100, -17}, writer)
+	require.NoError(t, err)
+
+	facts := getFact(writer)
+	slices.Sort(facts)
+	require.Equal(t, []string{"-17,-1;17", "100,2;2;5;5"}, facts)
+}
+
+func TestEnvDefaultsWorkers(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const (
+		factWorkers  = 20
+		writeWorkers = 200
+	)
+
+	t.Setenv("FACT_WORKERS", "20")
+	t.Setenv("FACT_WRITE_WORKERS", "200")
+
+	fact, err := New(WithEnvDefaults())
+	require.NoError(t, err)
+
+	gNum := inspectNumGoroutines(t, func() {
+		err := fact.Factorize(context.Background(), generateNumbers(500), newSleepWriter(time.Millisecond*500))
+		require.NoError(t, err)
+	})
+
+	require.InDelta(t, factWorkers+writeWorkers, gNum, 20)
+}
+
+func TestEnvDefaultsOverriddenByOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const writeWorkers = 50
+
+	t.Setenv("FACT_WORKERS", "1")
+	t.Setenv("FACT_WRITE_WORKERS", "500")
+
+	// options after WithEnvDefaults take precedence over the environment
+	fact, err := New(
+		WithEnvDefaults(),
+		WithWriteWorkers(writeWorkers),
+	)
+	require.NoError(t, err)
+
+	gNum := inspectNumGoroutines(t, func() {
+		err := fact.Factorize(context.Background(), generateNumbers(500), newSleepWriter(time.Millisecond*500))
+		require.NoError(t, err)
+	})
+
+	require.InDelta(t, 1+writeWorkers, gNum, 20)
+}
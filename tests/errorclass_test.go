@@ -0,0 +1,187 @@
+//go:build errorclass_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errReset   = errors.New("connection reset")
+	errDropped = errors.New("line dropped by sink")
+	errFatal   = errors.New("sink closed")
+)
+
+// scriptedWriter fails the writes of selected numbers with a fixed error,
+// each for a limited number of attempts.
+type scriptedWriter struct {
+	sb       *strings.Builder
+	mx       *sync.RWMutex
+	script   func(n int) (int, error)
+	attempts map[int]int
+}
+
+func newScriptedWriter(script func(n int) (int, error)) *scriptedWriter {
+	return &scriptedWriter{
+		sb:       new(strings.Builder),
+		mx:       new(sync.RWMutex),
+		script:   script,
+		attempts: make(map[int]int),
+	}
+}
+
+func (s *scriptedWriter) Write(p []byte) (n int, err error) {
+	left, _, _ := strings.Cut(string(p), " = ")
+	num, _ := strconv.Atoi(left)
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.attempts[num]++
+	if times, err := s.script(num); err != nil && s.attempts[num] <= times {
+		return 0, err
+	}
+
+	return s.sb.Write(p)
+}
+
+func (s *scriptedWriter) String() string {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	return s.sb.String()
+}
+
+func classifyTestErrors(err error) ErrorClass {
+	switch {
+	case errors.Is(err, errReset):
+		return ErrorRetryable
+	case errors.Is(err, errDropped):
+		return ErrorIgnorable
+	default:
+		return ErrorFatal
+	}
+}
+
+func newClassifyingFactorizer(t *testing.T) *factorizerImpl {
+	t.Helper()
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(4),
+		WithWriterErrorClassifier(classifyTestErrors),
+		WithWriterRetry(3, time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	return fact
+}
+
+func TestWriterErrorClassifierInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithWriterErrorClassifier(nil))
+
+	require.ErrorContains(t, err, "classifier")
+}
+
+func TestWriterErrorClassifierRetryable(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	writer := newScriptedWriter(func(n int) (int, error) {
+		if n%5 == 0 {
+			return 2, errReset
+		}
+
+		return 0, nil
+	})
+
+	numbers := generateNumbers(1000)
+
+	err := newClassifyingFactorizer(t).Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	require.Len(t, getFact(writer), len(numbers))
+}
+
+func TestWriterErrorClassifierIgnorable(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	writer := newScriptedWriter(func(n int) (int, error) {
+		if n%3 == 0 {
+			return math.MaxInt32, errDropped
+		}
+
+		return 0, nil
+	})
+
+	numbers := generateNumbers(1000)
+
+	err := newClassifyingFactorizer(t).Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	// ignorable failures drop only their own line and are not retried
+	nums := make([]int, 0, len(numbers))
+	for _, line := range getFact(writer) {
+		num, _ := parseLine(t, line)
+		nums = append(nums, num)
+	}
+
+	want := slices.DeleteFunc(slices.Clone(numbers), func(n int) bool {
+		return n%3 == 0
+	})
+
+	slices.Sort(nums)
+	require.Equal(t, want, nums)
+
+	for _, n := range numbers {
+		if n%3 == 0 {
+			require.Equal(t, 1, writer.attempts[n], "ignorable write of %d was retried", n)
+		}
+	}
+}
+
+func TestWriterErrorClassifierFatal(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	writer := newScriptedWriter(func(n int) (int, error) {
+		if n == 500 {
+			return math.MaxInt32, errFatal
+		}
+
+		return 0, nil
+	})
+
+	err := newClassifyingFactorizer(t).Factorize(context.Background(), generateNumbers(100_000), writer)
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errFatal)
+
+	// fatal errors abort at once, without retries
+	require.Equal(t, 1, writer.attempts[500])
+}
+
+func TestWriterErrorClassifierRetriesExhausted(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	writer := newScriptedWriter(func(n int) (int, error) {
+		if n == 10 {
+			return math.MaxInt32, errReset
+		}
+
+		return 0, nil
+	})
+
+	err := newClassifyingFactorizer(t).Factorize(context.Background(), generateNumbers(100), writer)
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errReset)
+}
@@ -0,0 +1,166 @@
+//go:build errorpolicy_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errRejected = errors.New("rejected by sink")
+
+type rejectingWriter struct {
+	sb     *strings.Builder
+	mx     *sync.RWMutex
+	reject func(n int) bool
+}
+
+func newRejectingWriter(reject func(n int) bool) *rejectingWriter {
+	return &rejectingWriter{
+		sb:     new(strings.Builder),
+		mx:     new(sync.RWMutex),
+		reject: reject,
+	}
+}
+
+func (r *rejectingWriter) Write(p []byte) (n int, err error) {
+	left, _, _ := strings.Cut(string(p), " = ")
+	num, err := strconv.Atoi(left)
+	if err == nil && r.reject(num) {
+		return 0, errRejected
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	return r.sb.Write(p)
+}
+
+func (r *rejectingWriter) String() string {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	return r.sb.String()
+}
+
+func isEven(n int) bool {
+	return n%2 == 0
+}
+
+// writerErrorNumbers collects N from every WriterError in the tree of err.
+func writerErrorNumbers(err error) []int {
+	var res []int
+
+	switch e := err.(type) {
+	case *WriterError:
+		res = append(res, e.N)
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			res = append(res, writerErrorNumbers(inner)...)
+		}
+	case interface{ Unwrap() error }:
+		res = append(res, writerErrorNumbers(e.Unwrap())...)
+	}
+
+	return res
+}
+
+func TestErrorPolicyContinueOnError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(10),
+		WithErrorPolicy(ContinueOnError),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(1000)
+	writer := newRejectingWriter(isEven)
+
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errRejected)
+
+	// every rejected number is reported, however the failures are joined
+	failed := writerErrorNumbers(err)
+	slices.Sort(failed)
+	failed = slices.Compact(failed)
+	require.Equal(t, slices.DeleteFunc(slices.Clone(numbers), func(n int) bool {
+		return !isEven(n)
+	}), failed)
+
+	written := make([]int, 0, len(numbers)/2)
+	for _, line := range getFact(writer) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		written = append(written, num)
+	}
+
+	slices.Sort(written)
+	require.Equal(t, slices.DeleteFunc(numbers, isEven), written)
+}
+
+func TestErrorPolicyContinueOnErrorNoFailures(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(WithErrorPolicy(ContinueOnError))
+	require.NoError(t, err)
+
+	writer := newWriter()
+	err = fact.Factorize(context.Background(), generateNumbers(100), writer)
+	require.NoError(t, err)
+	require.Len(t, getFact(writer), 100)
+}
+
+func TestErrorPolicyContinueOnErrorCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(10),
+		WithErrorPolicy(ContinueOnError),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	err = fact.Factorize(ctx, generateNumbers(1000), newSleepErrorWriter(time.Millisecond*10, errRejected))
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+	require.ErrorIs(t, err, ErrWriterInteraction)
+}
+
+func TestErrorPolicyFailFast(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(1),
+		WithErrorPolicy(FailFast),
+	)
+	require.NoError(t, err)
+
+	writer := newRejectingWriter(isEven)
+
+	err = fact.Factorize(context.Background(), generateNumbers(100_000), writer)
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errRejected)
+
+	require.Less(t, len(getFact(writer)), 100_000/2)
+}
+
+func TestErrorPolicyInvalid(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithErrorPolicy(ErrorPolicy(-1)))
+	require.ErrorContains(t, err, "error policy")
+}
@@ -0,0 +1,129 @@
+//go:build factapi_test
+
+package factapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// factorizerFunc is the kind of mock downstream modules are expected to write.
+type factorizerFunc func(ctx context.Context, numbers []int, w io.Writer) error
+
+func (f factorizerFunc) Factorize(ctx context.Context, numbers []int, w io.Writer) error {
+	return f(ctx, numbers, w)
+}
+
+var _ Factorizer = factorizerFunc(nil)
+
+// report is a consumer that receives its factorizer via dependency injection.
+type report struct {
+	f Factorizer
+}
+
+func (r report) run(ctx context.Context, numbers []int) (string, error) {
+	sb := new(strings.Builder)
+	if err := r.f.Factorize(ctx, numbers, sb); err != nil {
+		return "", fmt.Errorf("report: %w", err)
+	}
+
+	return sb.String(), nil
+}
+
+func TestFactorizerMock(t *testing.T) {
+	mock := factorizerFunc(func(_ context.Context, numbers []int, w io.Writer) error {
+		for _, n := range numbers {
+			if _, err := io.WriteString(w, Result{N: n, Factors: []int{n}}.String()+"\n"); err != nil {
+				return fmt.Errorf("%w: %w", ErrWriterInteraction, err)
+			}
+		}
+
+		return nil
+	})
+
+	out, err := report{f: mock}.run(context.Background(), []int{2, 3})
+	require.NoError(t, err)
+	require.Equal(t, "2 = 2\n3 = 3\n", out)
+
+	cancelled := factorizerFunc(func(_ context.Context, _ []int, _ io.Writer) error {
+		return fmt.Errorf("%w: %w", ErrFactorizationCancelled, context.Canceled)
+	})
+
+	_, err = report{f: cancelled}.run(context.Background(), []int{2})
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+	require.ErrorIs(t, err, context.Canceled)
+	require.NotErrorIs(t, err, ErrWriterInteraction)
+}
+
+func TestErrors(t *testing.T) {
+	require.Error(t, ErrFactorizationCancelled)
+	require.Error(t, ErrWriterInteraction)
+	require.False(t, errors.Is(ErrFactorizationCancelled, ErrWriterInteraction))
+	require.False(t, errors.Is(ErrWriterInteraction, ErrFactorizationCancelled))
+}
+
+func TestResultString(t *testing.T) {
+	testCases := []struct {
+		result Result
+		want   string
+	}{
+		{result: Result{N: 0, Factors: []int{0}}, want: "0 = 0"},
+		{result: Result{N: 1, Factors: []int{1}}, want: "1 = 1"},
+		{result: Result{N: 100, Factors: []int{2, 2, 5, 5}}, want: "100 = 2 * 2 * 5 * 5"},
+		{result: Result{N: -17, Factors: []int{-1, 17}}, want: "-17 = -1 * 17"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.want, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.result.String())
+		})
+	}
+}
+
+func TestNewConfig(t *testing.T) {
+	cfg, err := NewConfig()
+	require.NoError(t, err)
+	require.Equal(t, runtime.GOMAXPROCS(0), cfg.FactWorkers)
+	require.Equal(t, runtime.GOMAXPROCS(0), cfg.WriteWorkers)
+
+	cfg, err = NewConfig(WithFactorizationWorkers(3), WithWriteWorkers(5))
+	require.NoError(t, err)
+	require.Equal(t, Config{FactWorkers: 3, WriteWorkers: 5}, cfg)
+}
+
+func TestNewConfigInvalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		opts    []Option
+		keyword string
+		value   string
+	}{
+		{
+			name:    "factorization workers",
+			opts:    []Option{WithFactorizationWorkers(-1)},
+			keyword: "factorization",
+			value:   "-1",
+		},
+		{
+			name:    "write workers",
+			opts:    []Option{WithWriteWorkers(-1)},
+			keyword: "write",
+			value:   "-1",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewConfig(tt.opts...)
+			require.ErrorContains(t, err, tt.keyword)
+			require.ErrorContains(t, err, tt.value)
+		})
+	}
+}
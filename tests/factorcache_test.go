@@ -0,0 +1,115 @@
+//go:build factorcache_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var _ FactorCache = (*mapCache)(nil)
+
+func TestFactorCacheInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithFactorCache(nil))
+
+	require.ErrorContains(t, err, "cache")
+}
+
+func TestFactorCachePopulated(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	cache := newMapCache()
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(4),
+		WithFactorCache(cache),
+	)
+	require.NoError(t, err)
+
+	numbers := []int{100, -17, 25, 38, 0, 1}
+
+	err = fact.Factorize(context.Background(), numbers, newWriter())
+	require.NoError(t, err)
+
+	gets, hits, puts := cache.stats()
+	require.Equal(t, len(numbers), gets)
+	require.Zero(t, hits)
+	require.Equal(t, len(numbers), puts)
+
+	for _, n := range numbers {
+		f, ok := cache.Get(n)
+		require.True(t, ok, "%d is not cached", n)
+
+		if n < -1 || n > 1 {
+			require.True(t, checkFactorization(n, f), "%d cached as %v", n, f)
+		}
+	}
+}
+
+func TestFactorCacheServesHits(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	cache := newMapCache()
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(4),
+		WithFactorCache(cache),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(1000)
+
+	first := newWriter()
+	err = fact.Factorize(context.Background(), numbers, first)
+	require.NoError(t, err)
+
+	_, _, puts := cache.stats()
+
+	second := newWriter()
+	err = fact.Factorize(context.Background(), numbers, second)
+	require.NoError(t, err)
+
+	gets, hits, after := cache.stats()
+	require.Equal(t, 2*len(numbers), gets)
+	require.Equal(t, len(numbers), hits)
+	require.Equal(t, puts, after, "cache hits must not be stored again")
+
+	want, got := getFact(first), getFact(second)
+	slices.Sort(want)
+	slices.Sort(got)
+	require.Equal(t, want, got)
+}
+
+func TestFactorCacheTrusted(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	// a wrong entry shows that cached factors are written without being recomputed
+	cache := newMapCache()
+	cache.Put(91, []int{7, 11})
+
+	fact, err := New(
+		WithFactorizationWorkers(1),
+		WithWriteWorkers(1),
+		WithFactorCache(cache),
+	)
+	require.NoError(t, err)
+
+	writer := newWriter()
+	err = fact.Factorize(context.Background(), []int{91, 91, 92}, writer)
+	require.NoError(t, err)
+
+	facts := getFact(writer)
+	slices.Sort(facts)
+	require.Equal(t, []string{"91 = 7 * 11", "91 = 7 * 11", "92 = 2 * 2 * 23"}, facts)
+
+	_, hits, puts := cache.stats()
+	require.Equal(t, 2, hits)
+	require.Equal(t, 2, puts, "only 92 must be stored besides the prefilled entry")
+}
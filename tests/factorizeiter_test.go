@@ -0,0 +1,226 @@
+//go:build factorizeiter_test
+
+package fact
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func collectResults(t *testing.T, fact *factorizerImpl, numbers []int) ([]Result, error) {
+	t.Helper()
+
+	var results []Result
+
+	for res, err := range fact.FactorizeIter(context.Background(), numbers) {
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+func TestFactorizeIterGolden(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name    string
+		numbers []int
+		want    []Result
+	}{
+		{
+			name:    "readme example",
+			numbers: []int{100, -17, 25, 38},
+			want: []Result{
+				{Index: 1, N: -17, Factors: []int{-1, 17}},
+				{Index: 2, N: 25, Factors: []int{5, 5}},
+				{Index: 3, N: 38, Factors: []int{2, 19}},
+				{Index: 0, N: 100, Factors: []int{2, 2, 5, 5}},
+			},
+		},
+		{
+			name:    "edge values",
+			numbers: []int{1, 0, -1},
+			want: []Result{
+				{Index: 2, N: -1, Factors: []int{-1}},
+				{Index: 1, N: 0, Factors: []int{0}},
+				{Index: 0, N: 1, Factors: []int{1}},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := collectResults(t, newFactorizer(t, 2, 2), tt.numbers)
+			require.NoError(t, err)
+
+			slices.SortFunc(got, func(a, b Result) int {
+				return cmp.Compare(a.N, b.N)
+			})
+
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFactorizeIterEmpty(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	for range newFactorizer(t, 2, 2).FactorizeIter(context.Background(), nil) {
+		require.FailNow(t, "nothing to yield")
+	}
+}
+
+func TestFactorizeIterCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := generateNumbers(10_000)
+
+	got, err := collectResults(t, newFactorizer(t, 10, 10), numbers)
+	require.NoError(t, err)
+
+	nums := make([]int, 0, len(got))
+	for _, res := range got {
+		require.True(t, checkFactorization(res.N, res.Factors))
+		require.Equal(t, numbers[res.Index], res.N)
+
+		nums = append(nums, res.N)
+	}
+
+	slices.Sort(nums)
+	require.Equal(t, numbers, nums)
+}
+
+func TestFactorizeIterIndices(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name    string
+		numbers []int
+	}{
+		{name: "distinct", numbers: []int{100, -17, 25, 38}},
+		{name: "repeated inputs", numbers: []int{4, 4, 9, 4, 9}},
+		{name: "large", numbers: generateNumbers(10_000)},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := collectResults(t, newFactorizer(t, 10, 10), tt.numbers)
+			require.NoError(t, err)
+			require.Len(t, got, len(tt.numbers))
+
+			// results arrive in completion order; Index is the position in the input
+			ordered := make([]int, len(tt.numbers))
+			seen := make([]bool, len(tt.numbers))
+
+			for _, res := range got {
+				require.GreaterOrEqual(t, res.Index, 0)
+				require.Less(t, res.Index, len(tt.numbers))
+				require.False(t, seen[res.Index], "index %d is yielded twice", res.Index)
+
+				seen[res.Index] = true
+				ordered[res.Index] = res.N
+			}
+
+			require.Equal(t, tt.numbers, ordered)
+		})
+	}
+}
+
+// stallingFactorizer blocks every number from stallFrom upward until its context is done,
+// so that only the numbers below stallFrom can ever be yielded.
+func stallingFactorizer(t *testing.T, stallFrom int) *factorizerImpl {
+	t.Helper()
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(4),
+		WithFaultInjector(&scriptedFaults{
+			factorize: func(ctx context.Context, _, n int) {
+				if n >= stallFrom {
+					<-ctx.Done()
+				}
+			},
+		}),
+	)
+	require.NoError(t, err)
+
+	return fact
+}
+
+func TestFactorizeIterEarlyBreak(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const stallFrom = 100
+
+	for _, stopAfter := range []int{1, 10} {
+		seen := 0
+		start := time.Now()
+
+		// the stalled workers only return once breaking cancels the remaining work
+		numbers := generateNumbers(1000)
+
+		for res, err := range stallingFactorizer(t, stallFrom).FactorizeIter(context.Background(), numbers) {
+			require.NoError(t, err)
+			require.True(t, checkFactorization(res.N, res.Factors))
+			require.Less(t, res.N, stallFrom)
+			require.Equal(t, numbers[res.Index], res.N)
+
+			seen++
+			if seen == stopAfter {
+				break
+			}
+		}
+
+		require.Equal(t, stopAfter, seen)
+		require.Less(t, time.Since(start), time.Second)
+	}
+}
+
+func TestFactorizeIterCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const (
+		stallFrom   = 100
+		cancelAfter = 10
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	errs := 0
+	results := 0
+
+	for res, err := range stallingFactorizer(t, stallFrom).FactorizeIter(ctx, generateNumbers(1000)) {
+		if err != nil {
+			// cancellation is reported once as the final element
+			require.ErrorIs(t, err, ErrFactorizationCancelled)
+			require.ErrorIs(t, err, context.Canceled)
+			require.Zero(t, res.N)
+
+			errs++
+
+			continue
+		}
+
+		require.Zero(t, errs, "no results after the error")
+		require.Less(t, res.N, stallFrom)
+
+		results++
+		if results == cancelAfter {
+			cancel()
+		}
+	}
+
+	require.Equal(t, 1, errs)
+	require.GreaterOrEqual(t, results, cancelAfter)
+	require.LessOrEqual(t, results, stallFrom)
+}
@@ -0,0 +1,186 @@
+//go:build factpb_test
+
+package factpb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type factorizerFunc func(ctx context.Context, numbers []int, w io.Writer) error
+
+func (f factorizerFunc) Factorize(ctx context.Context, numbers []int, w io.Writer) error {
+	return f(ctx, numbers, w)
+}
+
+// scripted holds the factorizations the fixtures write. The adapter only moves lines
+// into messages, so it does not need a real factorization.
+var scripted = map[int]string{
+	100: "100 = 2 * 2 * 5 * 5\n",
+	-17: "-17 = -1 * 17\n",
+	25:  "25 = 5 * 5\n",
+	38:  "38 = 2 * 19\n",
+	0:   "0 = 0\n",
+	1:   "1 = 1\n",
+}
+
+// sequential writes the scripted lines one at a time, in input order.
+var sequential = factorizerFunc(func(ctx context.Context, numbers []int, w io.Writer) error {
+	for _, n := range numbers {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		line, ok := scripted[n]
+		if !ok {
+			return fmt.Errorf("%d is not scripted", n)
+		}
+
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+})
+
+func newClient(t *testing.T, f factorizerFunc) FactClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+
+	srv := grpc.NewServer()
+	RegisterFactServer(srv, NewServer(f))
+
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return NewFactClient(conn)
+}
+
+func factorize(ctx context.Context, client FactClient, numbers []int) ([]string, error) {
+	stream, err := client.Factorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range numbers {
+		if err := stream.Send(&Number{Value: int64(n)}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	var res []string
+
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return res, nil
+		}
+
+		if err != nil {
+			return res, err
+		}
+
+		factors := make([]string, 0, len(msg.GetFactors()))
+		for _, f := range msg.GetFactors() {
+			factors = append(factors, strconv.FormatInt(f, 10))
+		}
+
+		res = append(res, fmt.Sprintf("%d = %s", msg.GetN(), strings.Join(factors, " * ")))
+	}
+}
+
+func TestServerFactorize(t *testing.T) {
+	client := newClient(t, sequential)
+
+	res, err := factorize(context.Background(), client, []int{100, -17, 25, 38, 0, 1})
+	require.NoError(t, err)
+
+	slices.Sort(res)
+	require.Equal(t, []string{
+		"-17 = -1 * 17",
+		"0 = 0",
+		"1 = 1",
+		"100 = 2 * 2 * 5 * 5",
+		"25 = 5 * 5",
+		"38 = 2 * 19",
+	}, res)
+}
+
+func TestServerEmptyStream(t *testing.T) {
+	client := newClient(t, sequential)
+
+	res, err := factorize(context.Background(), client, nil)
+	require.NoError(t, err)
+	require.Empty(t, res)
+}
+
+func TestServerDeadline(t *testing.T) {
+	cancelled := make(chan error, 1)
+
+	client := newClient(t, func(ctx context.Context, _ []int, _ io.Writer) error {
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	_, err := factorize(ctx, client, []int{1, 2, 3})
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	select {
+	case err := <-cancelled:
+		require.Error(t, err)
+	case <-time.After(time.Second):
+		require.FailNow(t, "factorization was not cancelled by the RPC deadline")
+	}
+}
+
+func TestServerFactorizerError(t *testing.T) {
+	client := newClient(t, func(_ context.Context, _ []int, _ io.Writer) error {
+		return errors.New("factorizer broken")
+	})
+
+	_, err := factorize(context.Background(), client, []int{1, 2, 3})
+	require.Error(t, err)
+	require.NotEqual(t, codes.OK, status.Code(err))
+}
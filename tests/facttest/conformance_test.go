@@ -0,0 +1,139 @@
+//go:build facttest_test
+
+package facttest
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type lockedWriter struct {
+	sb *strings.Builder
+	mx *sync.Mutex
+}
+
+func newLockedWriter() *lockedWriter {
+	return &lockedWriter{
+		sb: new(strings.Builder),
+		mx: new(sync.Mutex),
+	}
+}
+
+func (l *lockedWriter) Write(p []byte) (n int, err error) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	return l.sb.Write(p)
+}
+
+type writerFunc func(p []byte) (n int, err error)
+
+func (f writerFunc) Write(p []byte) (n int, err error) {
+	return f(p)
+}
+
+func TestCheckWriterConformance(t *testing.T) {
+	errWrite := errors.New("disk full")
+
+	testCases := []struct {
+		name   string
+		writer io.Writer
+		err    error
+	}{
+		{
+			name:   "locked writer",
+			writer: newLockedWriter(),
+		},
+		{
+			name:   "discard",
+			writer: io.Discard,
+		},
+		{
+			name: "error with partial count",
+			writer: writerFunc(func(p []byte) (int, error) {
+				return len(p) / 2, errWrite
+			}),
+		},
+		{
+			name: "short write without error",
+			writer: writerFunc(func(p []byte) (int, error) {
+				return len(p) / 2, nil
+			}),
+			err: ErrShortWriteWithoutError,
+		},
+		{
+			name: "negative count",
+			writer: writerFunc(func(_ []byte) (int, error) {
+				return -1, nil
+			}),
+			err: ErrInvalidCount,
+		},
+		{
+			name: "count above len",
+			writer: writerFunc(func(p []byte) (int, error) {
+				return len(p) + 1, nil
+			}),
+			err: ErrInvalidCount,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckWriterConformance(tt.writer)
+			if tt.err == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestCheckWriterConformanceBlocking(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() {
+		close(release)
+	})
+
+	writer := writerFunc(func(p []byte) (int, error) {
+		<-release
+
+		return len(p), nil
+	})
+
+	start := time.Now()
+
+	err := CheckWriterConformance(writer, WithWriteTimeout(100*time.Millisecond))
+	require.ErrorIs(t, err, ErrWriteBlocked)
+
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestCheckWriterConformanceReportsAll(t *testing.T) {
+	calls := 0
+	mx := new(sync.Mutex)
+
+	writer := writerFunc(func(p []byte) (int, error) {
+		mx.Lock()
+		defer mx.Unlock()
+
+		calls++
+		if calls%2 == 0 {
+			return -1, nil
+		}
+
+		return len(p) / 2, nil
+	})
+
+	err := CheckWriterConformance(writer)
+	require.ErrorIs(t, err, ErrShortWriteWithoutError)
+	require.ErrorIs(t, err, ErrInvalidCount)
+}
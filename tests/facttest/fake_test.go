@@ -0,0 +1,122 @@
+//go:build fake_test
+
+package facttest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type failingWriter struct {
+	err error
+}
+
+func (f failingWriter) Write(_ []byte) (n int, err error) {
+	return 0, f.err
+}
+
+var fakeResults = map[int][]int{
+	100: {2, 2, 5, 5},
+	-17: {-1, 17},
+	25:  {5, 5},
+	38:  {2, 19},
+}
+
+func TestFakeScriptedOutput(t *testing.T) {
+	fake := NewFake(fakeResults)
+
+	writer := new(strings.Builder)
+	err := fake.Factorize(context.Background(), []int{100, -17, 25, 38}, writer)
+	require.NoError(t, err)
+
+	// the fake writes sequentially, so the output keeps the input order
+	require.Equal(t, "100 = 2 * 2 * 5 * 5\n-17 = -1 * 17\n25 = 5 * 5\n38 = 2 * 19\n", writer.String())
+}
+
+func TestFakeUnscriptedNumber(t *testing.T) {
+	fake := NewFake(fakeResults)
+
+	writer := new(strings.Builder)
+	err := fake.Factorize(context.Background(), []int{100, 7}, writer)
+
+	var unscripted *UnscriptedNumberError
+	require.ErrorAs(t, err, &unscripted)
+	require.Equal(t, 7, unscripted.N)
+	require.ErrorContains(t, err, "7")
+
+	require.Equal(t, "100 = 2 * 2 * 5 * 5\n", writer.String())
+}
+
+func TestFakeRecordsCalls(t *testing.T) {
+	fake := NewFake(fakeResults)
+
+	require.Empty(t, fake.Calls())
+
+	require.NoError(t, fake.Factorize(context.Background(), []int{25}, io.Discard))
+	require.NoError(t, fake.Factorize(context.Background(), []int{38, 100}, io.Discard))
+
+	require.Equal(t, [][]int{{25}, {38, 100}}, fake.Calls())
+}
+
+func TestFakeDelay(t *testing.T) {
+	const delay = 50 * time.Millisecond
+
+	fake := NewFake(fakeResults, WithFakeDelay(delay))
+
+	start := time.Now()
+	require.NoError(t, fake.Factorize(context.Background(), []int{25, 38}, io.Discard))
+	require.GreaterOrEqual(t, time.Since(start), 2*delay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), delay/2)
+	t.Cleanup(cancel)
+
+	writer := new(strings.Builder)
+	start = time.Now()
+
+	err := fake.Factorize(ctx, []int{25, 38}, writer)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), delay)
+	require.Empty(t, writer.String())
+}
+
+func TestFakeInjectedError(t *testing.T) {
+	errInjected := errors.New("injected")
+
+	fake := NewFake(fakeResults, WithFakeError(1, errInjected))
+
+	writer := new(strings.Builder)
+	err := fake.Factorize(context.Background(), []int{100, -17, 25}, writer)
+	require.ErrorIs(t, err, errInjected)
+
+	// the error is returned after the given number of lines
+	require.Equal(t, "100 = 2 * 2 * 5 * 5\n", writer.String())
+}
+
+func TestFakeWriterError(t *testing.T) {
+	errWrite := errors.New("disk full")
+
+	fake := NewFake(fakeResults)
+
+	err := fake.Factorize(context.Background(), []int{25}, failingWriter{err: errWrite})
+	require.ErrorIs(t, err, errWrite)
+}
+
+func TestFakeCopiesResults(t *testing.T) {
+	results := map[int][]int{4: {2, 2}}
+	fake := NewFake(results)
+
+	results[4][0] = 3
+	results[9] = []int{3, 3}
+
+	writer := new(strings.Builder)
+	require.NoError(t, fake.Factorize(context.Background(), []int{4}, writer))
+	require.Equal(t, "4 = 2 * 2", strings.TrimRight(writer.String(), "\n"))
+
+	require.Error(t, fake.Factorize(context.Background(), []int{9}, io.Discard))
+}
@@ -0,0 +1,169 @@
+//go:build writers_test
+
+package facttest
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafeWriterConcurrent(t *testing.T) {
+	const (
+		writers = 50
+		lines   = 100
+	)
+
+	w := NewSafeWriter()
+	errs := make([]error, writers)
+
+	wg := new(sync.WaitGroup)
+	for i := range writers {
+		wg.Go(func() {
+			for j := range lines {
+				if _, err := io.WriteString(w, strconv.Itoa(i*lines+j)+"\n"); err != nil {
+					errs[i] = err
+
+					return
+				}
+			}
+		})
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	got := strings.Split(strings.TrimRight(w.String(), "\n"), "\n")
+	require.Len(t, got, writers*lines)
+
+	// every write lands in one piece
+	nums := make([]int, 0, len(got))
+	for _, line := range got {
+		n, err := strconv.Atoi(line)
+		require.NoError(t, err)
+
+		nums = append(nums, n)
+	}
+
+	slices.Sort(nums)
+
+	for i, n := range nums {
+		require.Equal(t, i, n)
+	}
+}
+
+func TestSafeWriterLines(t *testing.T) {
+	w := NewSafeWriter()
+	require.Empty(t, w.Lines())
+
+	_, err := io.WriteString(w, "1 = 1\n2 = 2\n")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"1 = 1", "2 = 2"}, w.Lines())
+}
+
+func TestDelayWriter(t *testing.T) {
+	const delay = 20 * time.Millisecond
+
+	w := NewDelayWriter(delay)
+
+	start := time.Now()
+
+	for range 3 {
+		n, err := io.WriteString(w, "4 = 2 * 2\n")
+		require.NoError(t, err)
+		require.Equal(t, len("4 = 2 * 2\n"), n)
+	}
+
+	require.GreaterOrEqual(t, time.Since(start), 3*delay)
+	require.Equal(t, []string{"4 = 2 * 2", "4 = 2 * 2", "4 = 2 * 2"}, w.Lines())
+}
+
+func TestDelayWriterConcurrent(t *testing.T) {
+	const delay = 100 * time.Millisecond
+
+	w := NewDelayWriter(delay)
+
+	start := time.Now()
+
+	// the delay is applied outside the lock, so concurrent writes overlap
+	errs := make([]error, 10)
+
+	wg := new(sync.WaitGroup)
+	for i := range errs {
+		wg.Go(func() {
+			_, errs[i] = io.WriteString(w, "1 = 1\n")
+		})
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	require.Less(t, time.Since(start), 5*delay)
+	require.Len(t, w.Lines(), 10)
+}
+
+func TestErrAfterNWriter(t *testing.T) {
+	errWrite := errors.New("disk full")
+
+	testCases := []struct {
+		name  string
+		n     int
+		calls int
+		lines []string
+	}{
+		{
+			name:  "fail immediately",
+			n:     0,
+			calls: 3,
+			lines: []string{},
+		},
+		{
+			name:  "fail after two",
+			n:     2,
+			calls: 4,
+			lines: []string{"0", "1"},
+		},
+		{
+			name:  "never reached",
+			n:     10,
+			calls: 3,
+			lines: []string{"0", "1", "2"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewErrAfterNWriter(tt.n, errWrite)
+
+			for i := range tt.calls {
+				n, err := io.WriteString(w, strconv.Itoa(i)+"\n")
+				if i < tt.n {
+					require.NoError(t, err)
+					require.Equal(t, 2, n)
+
+					continue
+				}
+
+				// failing writes report that nothing was written
+				require.ErrorIs(t, err, errWrite)
+				require.Zero(t, n)
+			}
+
+			require.Equal(t, tt.lines, w.Lines())
+		})
+	}
+}
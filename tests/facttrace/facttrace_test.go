@@ -0,0 +1,201 @@
+//go:build facttrace_test
+
+package facttrace
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const traceNumberKey = attribute.Key("fact.number")
+
+// callHook mirrors fact.CallHook. Its methods only use standard types, so
+// *Hook satisfies it without facttrace importing package fact.
+type callHook interface {
+	CallStarted(ctx context.Context, count int) context.Context
+	NumberFactorized(ctx context.Context, n int, elapsed time.Duration)
+	CallFinished(ctx context.Context, err error)
+}
+
+var _ callHook = (*Hook)(nil)
+
+func newRecordingProvider(t *testing.T) (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	t.Cleanup(func() {
+		require.NoError(t, provider.Shutdown(context.Background()))
+	})
+
+	return provider, recorder
+}
+
+func factorizeSpan(t *testing.T, recorder *tracetest.SpanRecorder) sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	var found []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == "Factorize" {
+			found = append(found, span)
+		}
+	}
+
+	require.Len(t, found, 1, "exactly one span per Factorize call")
+
+	return found[0]
+}
+
+func numberAttr(attrs []attribute.KeyValue) (int, bool) {
+	for _, attr := range attrs {
+		if attr.Key == traceNumberKey {
+			return int(attr.Value.AsInt64()), true
+		}
+	}
+
+	return 0, false
+}
+
+// slowNumbers collects the numbers reported as slow, either as child spans
+// of the Factorize span or as events on it.
+func slowNumbers(recorder *tracetest.SpanRecorder, root sdktrace.ReadOnlySpan) []int {
+	var res []int
+
+	for _, span := range recorder.Ended() {
+		if span.Parent().SpanID() != root.SpanContext().SpanID() {
+			continue
+		}
+
+		if n, ok := numberAttr(span.Attributes()); ok {
+			res = append(res, n)
+		}
+	}
+
+	for _, event := range root.Events() {
+		if n, ok := numberAttr(event.Attributes); ok {
+			res = append(res, n)
+		}
+	}
+
+	slices.Sort(res)
+
+	return res
+}
+
+// runCall drives the hook the way the pipeline does for one Factorize call.
+func runCall(ctx context.Context, hook *Hook, elapsed map[int]time.Duration, numbers []int, err error) {
+	ctx = hook.CallStarted(ctx, len(numbers))
+
+	for _, n := range numbers {
+		hook.NumberFactorized(ctx, n, elapsed[n])
+	}
+
+	hook.CallFinished(ctx, err)
+}
+
+func TestNewInvalid(t *testing.T) {
+	t.Run("provider", func(t *testing.T) {
+		_, err := New(nil)
+
+		require.ErrorContains(t, err, "tracer provider")
+	})
+
+	t.Run("threshold", func(t *testing.T) {
+		provider, _ := newRecordingProvider(t)
+
+		_, err := New(provider, WithSlowThreshold(-time.Second))
+
+		require.ErrorContains(t, err, "threshold")
+		require.ErrorContains(t, err, "-1s")
+	})
+}
+
+func TestFactorizeSpan(t *testing.T) {
+	provider, recorder := newRecordingProvider(t)
+
+	hook, err := New(provider)
+	require.NoError(t, err)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+
+	runCall(ctx, hook, nil, []int{100, -17, 25, 38}, nil)
+
+	parent.End()
+
+	span := factorizeSpan(t, recorder)
+	require.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID(), "Factorize span must be a child of the caller's span")
+	require.NotEqual(t, codes.Error, span.Status().Code)
+
+	// the default threshold is high enough for fast numbers not to be reported
+	require.Empty(t, slowNumbers(recorder, span))
+}
+
+func TestSlowFactorizations(t *testing.T) {
+	elapsed := map[int]time.Duration{
+		100: time.Second,
+		-17: time.Millisecond,
+		25:  time.Second,
+		4:   time.Second,
+	}
+
+	testCases := []struct {
+		name      string
+		threshold time.Duration
+		numbers   []int
+		want      []int
+	}{
+		{
+			name:      "above threshold",
+			threshold: time.Millisecond * 100,
+			numbers:   []int{100, -17, 25},
+			want:      []int{25, 100},
+		},
+		{
+			name:      "nothing is slow",
+			threshold: time.Hour,
+			numbers:   []int{100, -17, 25},
+			want:      nil,
+		},
+		{
+			name:      "repeated inputs",
+			threshold: time.Millisecond * 100,
+			numbers:   []int{4, 4},
+			want:      []int{4, 4},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, recorder := newRecordingProvider(t)
+
+			hook, err := New(provider, WithSlowThreshold(tt.threshold))
+			require.NoError(t, err)
+
+			runCall(context.Background(), hook, elapsed, tt.numbers, nil)
+
+			require.Equal(t, tt.want, slowNumbers(recorder, factorizeSpan(t, recorder)))
+		})
+	}
+}
+
+func TestFailedCall(t *testing.T) {
+	provider, recorder := newRecordingProvider(t)
+
+	hook, err := New(provider)
+	require.NoError(t, err)
+
+	runCall(context.Background(), hook, nil, []int{4}, errors.New("cancelled"))
+
+	// CallFinished ends the span
+	require.Equal(t, codes.Error, factorizeSpan(t, recorder).Status().Code)
+}
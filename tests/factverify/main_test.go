@@ -0,0 +1,125 @@
+//go:build factverify_test
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func runCommand(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+
+	stdout, stderr := new(strings.Builder), new(strings.Builder)
+	code := run(args, stdout, stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func TestFactverifyValidOutput(t *testing.T) {
+	output := writeFile(t, "out.txt", "100 = 2 * 2 * 5 * 5\n-17 = -1 * 17\n25 = 5 * 5\n38 = 2 * 19\n")
+
+	code, stdout, stderr := runCommand(t, output)
+
+	require.Equal(t, 0, code)
+	require.Empty(t, stderr)
+	require.Contains(t, stdout, "4 lines")
+}
+
+func TestFactverifyInvalidOutput(t *testing.T) {
+	output := writeFile(t, "out.txt", "4 = 2 * 2\n12 = 2 * 6\n5 = 5\ngarbage\n")
+
+	code, _, stderr := runCommand(t, output)
+
+	require.Equal(t, 1, code)
+
+	// every bad line is reported with its line number and text
+	require.Contains(t, stderr, "line 2")
+	require.Contains(t, stderr, "12 = 2 * 6")
+	require.Contains(t, stderr, "line 4")
+	require.Contains(t, stderr, "garbage")
+	require.NotContains(t, stderr, "line 1")
+	require.NotContains(t, stderr, "line 3")
+}
+
+func TestFactverifyInputCompleteness(t *testing.T) {
+	output := writeFile(t, "out.txt", "4 = 2 * 2\n7 = 7\n")
+
+	testCases := []struct {
+		name  string
+		input string
+		code  int
+		want  []string
+	}{
+		{
+			name:  "complete",
+			input: "4\n7\n",
+			code:  0,
+		},
+		{
+			name:  "missing",
+			input: "4\n5\n7\n",
+			code:  1,
+			want:  []string{"missing", "5"},
+		},
+		{
+			name:  "extra",
+			input: "4\n",
+			code:  1,
+			want:  []string{"extra", "7"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			input := writeFile(t, "in.txt", tt.input)
+
+			code, _, stderr := runCommand(t, "-input", input, output)
+
+			require.Equal(t, tt.code, code)
+
+			for _, want := range tt.want {
+				require.Contains(t, stderr, want)
+			}
+		})
+	}
+}
+
+func TestFactverifyUsageErrors(t *testing.T) {
+	output := writeFile(t, "out.txt", "4 = 2 * 2\n")
+
+	testCases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "no arguments", args: nil, want: "usage"},
+		{name: "too many arguments", args: []string{output, output}, want: "usage"},
+		{name: "unknown flag", args: []string{"-bogus", output}, want: "bogus"},
+		{name: "missing output file", args: []string{filepath.Join(t.TempDir(), "absent.txt")}, want: "absent.txt"},
+		{name: "malformed input file", args: []string{"-input", writeFile(t, "in.txt", "4\nfour\n"), output}, want: "four"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCommand(t, tt.args...)
+
+			// 2 separates operational errors from a failed verification
+			require.Equal(t, 2, code)
+			require.Contains(t, stderr, tt.want)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+//go:build fanout_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func checkAllNumbers(t *testing.T, writer TestWriter, numbers []int) {
+	t.Helper()
+
+	allNums := make([]int, 0, len(numbers))
+	for _, line := range getFact(writer) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		allNums = append(allNums, num)
+	}
+
+	slices.Sort(allNums)
+	require.Equal(t, numbers, allNums)
+}
+
+func TestAdditionalWritersInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithAdditionalWriters(newWriter(), nil))
+
+	require.ErrorContains(t, err, "writer")
+}
+
+func TestAdditionalWritersReceiveEveryLine(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	first, second := newWriter(), newWriter()
+
+	fact, err := New(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(10),
+		WithAdditionalWriters(first, second),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(10_000)
+	primary := newWriter()
+
+	err = fact.Factorize(context.Background(), numbers, primary)
+	require.NoError(t, err)
+
+	for _, writer := range []TestWriter{primary, first, second} {
+		checkAllNumbers(t, writer, numbers)
+	}
+}
+
+func TestAdditionalWritersPrimaryFailureAborts(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	errPrimary := errors.New("primary unavailable")
+
+	fact, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithAdditionalWriters(newWriter()),
+	)
+	require.NoError(t, err)
+
+	err = fact.Factorize(context.Background(), generateNumbers(1000), newSleepErrorWriter(time.Millisecond, errPrimary))
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errPrimary)
+}
+
+func TestAdditionalWritersFailureDoesNotAbort(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	healthy := newWriter()
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(4),
+		WithAdditionalWriters(newSleepErrorWriter(0, errors.New("mirror unavailable")), healthy),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(1000)
+	primary := newWriter()
+
+	err = fact.Factorize(context.Background(), numbers, primary)
+	require.NoError(t, err, "a failing additional writer must not abort the job")
+
+	checkAllNumbers(t, primary, numbers)
+	checkAllNumbers(t, healthy, numbers)
+}
+
+func TestAdditionalWritersCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	mirror := newWriter()
+
+	fact, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithAdditionalWriters(mirror),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	primary := newSleepWriter(time.Millisecond)
+
+	err = fact.Factorize(ctx, generateNumbers(1_000_000), primary)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	// a line reaches the additional writers only after the primary accepted it
+	require.LessOrEqual(t, len(getFact(mirror)), len(getFact(primary)))
+}
@@ -0,0 +1,178 @@
+//go:build faultinjection_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultInjectorInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithFaultInjector(nil))
+
+	require.ErrorContains(t, err, "fault injector")
+}
+
+func TestFaultInjectorNoFaults(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(4),
+		WithFaultInjector(&scriptedFaults{}),
+	)
+	require.NoError(t, err)
+
+	writer := newWriter()
+	require.NoError(t, fact.Factorize(context.Background(), generateNumbers(1000), writer))
+
+	lines := getFact(writer)
+	require.Len(t, lines, 1000)
+
+	for _, line := range lines {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+	}
+}
+
+func TestFaultInjectorFailKthWrite(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const k = 5
+
+	errInjected := errors.New("injected write fault")
+
+	for range 20 {
+		fact, err := New(
+			WithFactorizationWorkers(4),
+			WithWriteWorkers(1),
+			WithFaultInjector(&scriptedFaults{
+				write: func(_ context.Context, i int) error {
+					if i == k {
+						return errInjected
+					}
+
+					return nil
+				},
+			}),
+		)
+		require.NoError(t, err)
+
+		writer := newWriter()
+
+		err = fact.Factorize(context.Background(), generateNumbers(1000), writer)
+		require.ErrorIs(t, err, ErrWriterInteraction)
+		require.ErrorIs(t, err, errInjected)
+
+		// with a single write worker the failure is deterministic
+		require.Len(t, getFact(writer), k)
+	}
+}
+
+func TestFaultInjectorDelayDispatch(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const (
+		numbers = 10
+		delay   = 10 * time.Millisecond
+	)
+
+	dispatched := make([]int, 0, numbers)
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(4),
+		WithFaultInjector(&scriptedFaults{
+			dispatch: func(_ context.Context, n int) {
+				// the dispatcher is a single goroutine, so no locking is needed
+				dispatched = append(dispatched, n)
+
+				time.Sleep(delay)
+			},
+		}),
+	)
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	writer := newWriter()
+	require.NoError(t, fact.Factorize(context.Background(), generateNumbers(numbers), writer))
+
+	require.GreaterOrEqual(t, time.Since(start), numbers*delay)
+	require.Equal(t, generateNumbers(numbers), dispatched)
+	require.Len(t, getFact(writer), numbers)
+}
+
+func TestFaultInjectorStallWorker(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const (
+		workers = 3
+		stalled = 0
+	)
+
+	var (
+		stalledOn atomic.Int64
+		seen      sync.Map
+	)
+
+	stalledOn.Store(-1)
+
+	fact, err := New(
+		WithFactorizationWorkers(workers),
+		WithWriteWorkers(2),
+		WithFaultInjector(&scriptedFaults{
+			factorize: func(ctx context.Context, worker, n int) {
+				seen.Store(worker, struct{}{})
+
+				if worker != stalled {
+					// slow the others down so the stalled worker is sure to get a number
+					time.Sleep(time.Millisecond)
+
+					return
+				}
+
+				// the stalled worker blocks on its first number until cancellation
+				if stalledOn.CompareAndSwap(-1, int64(n)) {
+					<-ctx.Done()
+				}
+			},
+		}),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	t.Cleanup(cancel)
+
+	writer := newWriter()
+
+	err = fact.Factorize(ctx, generateNumbers(100), writer)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	// the other workers process everything except the stalled number
+	lines := getFact(writer)
+	require.Len(t, lines, 99)
+
+	for _, line := range lines {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		require.NotEqual(t, stalledOn.Load(), int64(num))
+	}
+
+	seen.Range(func(key, _ any) bool {
+		worker, ok := key.(int)
+		require.True(t, ok)
+		require.GreaterOrEqual(t, worker, 0)
+		require.Less(t, worker, workers)
+
+		return true
+	})
+}
@@ -0,0 +1,78 @@
+//go:build fuzz_test
+
+package fact
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fuzzBoundaries are passed through unchanged; every other value is shifted down
+// so that trial division of a prime input stays fast.
+var fuzzBoundaries = []int{math.MinInt, math.MinInt + 1, -1, 0, 1, math.MaxInt}
+
+const fuzzMaxNumbers = 64
+
+func decodeFuzzNumbers(data []byte) []int {
+	numbers := make([]int, 0, fuzzMaxNumbers)
+
+	for chunk := range slices.Chunk(data, 8) {
+		if len(chunk) < 8 || len(numbers) == fuzzMaxNumbers {
+			break
+		}
+
+		n := int(binary.LittleEndian.Uint64(chunk))
+		if !slices.Contains(fuzzBoundaries, n) {
+			n >>= 32
+		}
+
+		numbers = append(numbers, n)
+	}
+
+	return numbers
+}
+
+func encodeFuzzNumbers(numbers ...int) []byte {
+	data := make([]byte, 0, len(numbers)*8)
+	for _, n := range numbers {
+		data = binary.LittleEndian.AppendUint64(data, uint64(n))
+	}
+
+	return data
+}
+
+func FuzzFactorize(f *testing.F) {
+	skipIfNot64Bit(f)
+
+	f.Add(encodeFuzzNumbers(fuzzBoundaries...))
+	f.Add(encodeFuzzNumbers(100, -17, 25, 38))
+	f.Add(encodeFuzzNumbers(generateNumbers(64)...))
+	f.Add([]byte{})
+
+	fact := newFactorizer(f, 4, 4)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		numbers := decodeFuzzNumbers(data)
+
+		writer := newWriter()
+		err := fact.Factorize(context.Background(), numbers, writer)
+		require.NoError(t, err)
+
+		allNums := make([]int, 0, len(numbers))
+		for _, line := range getFact(writer) {
+			num, res := parseLine(t, line)
+			require.True(t, checkFactorization(num, res), "invalid line %q", line)
+
+			allNums = append(allNums, num)
+		}
+
+		slices.Sort(allNums)
+		slices.Sort(numbers)
+		require.Equal(t, numbers, allNums)
+	})
+}
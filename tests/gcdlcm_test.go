@@ -0,0 +1,161 @@
+//go:build gcdlcm_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parsePairLine(t *testing.T, line string) (Pair, int, int) {
+	t.Helper()
+
+	left, right, found := strings.Cut(line, ": ")
+	require.True(t, found, "malformed pair line %q", line)
+
+	operands := delimiterStringsToSliceInt(t, strings.Fields(left))
+	results := delimiterStringsToSliceInt(t, strings.Fields(right))
+	require.Len(t, operands, 2, "line %q", line)
+	require.Len(t, results, 2, "line %q", line)
+
+	return Pair{A: operands[0], B: operands[1]}, results[0], results[1]
+}
+
+func TestGCDLCMViaFactors(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		a, b int
+		gcd  int
+		lcm  int
+	}{
+		{a: 12, b: 18, gcd: 6, lcm: 36},
+		{a: 18, b: 12, gcd: 6, lcm: 36},
+		{a: 17, b: 19, gcd: 1, lcm: 323},
+		{a: 64, b: 48, gcd: 16, lcm: 192},
+		{a: -12, b: 18, gcd: 6, lcm: 36},
+		{a: -12, b: -18, gcd: 6, lcm: 36},
+		{a: 1, b: 97, gcd: 1, lcm: 97},
+		{a: 0, b: 5, gcd: 5, lcm: 0},
+		{a: 0, b: 0, gcd: 0, lcm: 0},
+		{a: 1_000_000, b: 1_000_000, gcd: 1_000_000, lcm: 1_000_000},
+	}
+
+	fact := newFactorizer(t, 2, 2)
+
+	for _, tt := range testCases {
+		t.Run(strconv.Itoa(tt.a)+"_"+strconv.Itoa(tt.b), func(t *testing.T) {
+			got, err := fact.GCDViaFactors(context.Background(), tt.a, tt.b)
+			require.NoError(t, err)
+			require.Equal(t, tt.gcd, got)
+
+			got, err = fact.LCMViaFactors(context.Background(), tt.a, tt.b)
+			require.NoError(t, err)
+			require.Equal(t, tt.lcm, got)
+		})
+	}
+}
+
+func TestLCMViaFactorsOverflow(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := newFactorizer(t, 1, 1).LCMViaFactors(context.Background(), math.MaxInt, 2)
+	require.ErrorContains(t, err, "overflow")
+}
+
+func TestGCDLCMViaFactorsCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fact := newFactorizer(t, 1, 1)
+
+	_, err := fact.GCDViaFactors(ctx, 12, 18)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	_, err = fact.LCMViaFactors(ctx, 12, 18)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+}
+
+func TestGCDLCMAllGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	pairs := []Pair{{A: 12, B: 18}, {A: -4, B: 6}, {A: 0, B: 7}, {A: 12, B: 18}}
+	want := []string{
+		"12 18: 6 36",
+		"-4 6: 2 12",
+		"0 7: 7 0",
+		"12 18: 6 36",
+	}
+
+	writer := newWriter()
+	err := newFactorizer(t, 2, 2).GCDLCMAll(context.Background(), pairs, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	slices.Sort(lines)
+	slices.Sort(want)
+
+	require.Equal(t, want, lines)
+}
+
+func TestGCDLCMAllCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	pairs := make([]Pair, 0, 100*100)
+	for a := range 100 {
+		for b := range 100 {
+			pairs = append(pairs, Pair{A: a * 7, B: -b * 3})
+		}
+	}
+
+	writer := newWriter()
+	err := newFactorizer(t, 8, 8).GCDLCMAll(context.Background(), pairs, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	require.Len(t, lines, len(pairs))
+
+	for _, line := range lines {
+		pair, g, l := parsePairLine(t, line)
+		require.Equal(t, bruteGCD(pair.A, pair.B), g, "line %q", line)
+
+		if g != 0 {
+			require.Equal(t, max(pair.A, -pair.A)/g*max(pair.B, -pair.B), l, "line %q", line)
+		}
+	}
+}
+
+func TestGCDLCMAllErrors(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	pairs := make([]Pair, 100_000)
+	for i := range pairs {
+		pairs[i] = Pair{A: i, B: i + 1}
+	}
+
+	t.Run("writer", func(t *testing.T) {
+		errWrite := errors.New("sink closed")
+
+		err := newFactorizer(t, 2, 2).GCDLCMAll(context.Background(), pairs, newSleepErrorWriter(time.Millisecond, errWrite))
+		require.ErrorIs(t, err, ErrWriterInteraction)
+		require.ErrorIs(t, err, errWrite)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+		t.Cleanup(cancel)
+
+		err := newFactorizer(t, 2, 2).GCDLCMAll(ctx, pairs, newSleepWriter(time.Millisecond))
+		require.ErrorIs(t, err, ErrFactorizationCancelled)
+	})
+}
@@ -0,0 +1,262 @@
+//go:build httpapi_test
+
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type factorizerFunc func(ctx context.Context, numbers []int, w io.Writer) error
+
+func (f factorizerFunc) Factorize(ctx context.Context, numbers []int, w io.Writer) error {
+	return f(ctx, numbers, w)
+}
+
+func trialDivision(n int) []int {
+	if n == 0 || n == 1 || n == -1 {
+		return []int{n}
+	}
+
+	var res []int
+	if n < 0 {
+		res = append(res, -1)
+		n = -n
+	}
+
+	for d := 2; d*d <= n; d++ {
+		for n%d == 0 {
+			res = append(res, d)
+			n /= d
+		}
+	}
+
+	if n > 1 {
+		res = append(res, n)
+	}
+
+	return res
+}
+
+func formatLine(n int) string {
+	factors := trialDivision(n)
+
+	parts := make([]string, 0, len(factors))
+	for _, f := range factors {
+		parts = append(parts, strconv.Itoa(f))
+	}
+
+	return fmt.Sprintf("%d = %s\n", n, strings.Join(parts, " * "))
+}
+
+// sequential writes lines the same way the fact package does, one worker at a time.
+var sequential = factorizerFunc(func(ctx context.Context, numbers []int, w io.Writer) error {
+	for _, n := range numbers {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if _, err := io.WriteString(w, formatLine(n)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+})
+
+type resultLine struct {
+	N       int   `json:"n"`
+	Factors []int `json:"factors"`
+}
+
+func postNumbers(t *testing.T, url string, body string) *http.Response {
+	t.Helper()
+
+	resp, err := http.Post(url+"/factorize", "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = resp.Body.Close()
+	})
+
+	return resp
+}
+
+func decodeResults(t *testing.T, r io.Reader) []resultLine {
+	t.Helper()
+
+	var res []resultLine
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var line resultLine
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line), "line %q is not JSON", scanner.Text())
+
+		res = append(res, line)
+	}
+
+	require.NoError(t, scanner.Err())
+
+	return res
+}
+
+func TestHandlerFactorize(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(sequential))
+	t.Cleanup(srv.Close)
+
+	resp := postNumbers(t, srv.URL, "[100, -17, 25, 38, 0, 1]")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	require.Equal(t, []resultLine{
+		{N: 100, Factors: []int{2, 2, 5, 5}},
+		{N: -17, Factors: []int{-1, 17}},
+		{N: 25, Factors: []int{5, 5}},
+		{N: 38, Factors: []int{2, 19}},
+		{N: 0, Factors: []int{0}},
+		{N: 1, Factors: []int{1}},
+	}, decodeResults(t, resp.Body))
+}
+
+func TestHandlerEmptyInput(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(sequential))
+	t.Cleanup(srv.Close)
+
+	resp := postNumbers(t, srv.URL, "[]")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Empty(t, decodeResults(t, resp.Body))
+}
+
+func TestHandlerBadRequests(t *testing.T) {
+	srv := httptest.NewServer(NewHandler(sequential))
+	t.Cleanup(srv.Close)
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{name: "wrong method", method: http.MethodGet, path: "/factorize", status: http.StatusMethodNotAllowed},
+		{name: "unknown path", method: http.MethodPost, path: "/primes", body: "[1]", status: http.StatusNotFound},
+		{name: "not json", method: http.MethodPost, path: "/factorize", body: "100, -17", status: http.StatusBadRequest},
+		{name: "not an array", method: http.MethodPost, path: "/factorize", body: `{"n": 100}`, status: http.StatusBadRequest},
+		{name: "not integers", method: http.MethodPost, path: "/factorize", body: "[1.5]", status: http.StatusBadRequest},
+		{name: "overflow", method: http.MethodPost, path: "/factorize", body: "[92233720368547758070]", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+
+			t.Cleanup(func() {
+				_ = resp.Body.Close()
+			})
+
+			require.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+}
+
+func TestHandlerStreamsResults(t *testing.T) {
+	release := make(chan struct{})
+
+	f := factorizerFunc(func(ctx context.Context, numbers []int, w io.Writer) error {
+		if _, err := io.WriteString(w, formatLine(numbers[0])); err != nil {
+			return err
+		}
+
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		_, err := io.WriteString(w, formatLine(numbers[1]))
+
+		return err
+	})
+
+	srv := httptest.NewServer(NewHandler(f))
+	t.Cleanup(srv.Close)
+
+	resp := postNumbers(t, srv.URL, "[12, 13]")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	reader := bufio.NewReader(resp.Body)
+
+	// the first result must arrive while the second one is still being computed
+	line, err := reader.ReadBytes('\n')
+	require.NoError(t, err)
+
+	var first resultLine
+	require.NoError(t, json.Unmarshal(line, &first))
+	require.Equal(t, resultLine{N: 12, Factors: []int{2, 2, 3}}, first)
+
+	close(release)
+
+	require.Equal(t, []resultLine{{N: 13, Factors: []int{13}}}, decodeResults(t, reader))
+}
+
+func TestHandlerClientDisconnect(t *testing.T) {
+	cancelled := make(chan error, 1)
+
+	f := factorizerFunc(func(ctx context.Context, _ []int, _ io.Writer) error {
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+
+		return ctx.Err()
+	})
+
+	srv := httptest.NewServer(NewHandler(f))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"/factorize", strings.NewReader("[1, 2, 3]"))
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err == nil {
+		_ = resp.Body.Close()
+	}
+
+	select {
+	case err := <-cancelled:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		require.FailNow(t, "factorization was not cancelled with the request")
+	}
+}
+
+func TestHandlerFactorizerError(t *testing.T) {
+	errBroken := errors.New("factorizer broken")
+
+	f := factorizerFunc(func(_ context.Context, _ []int, _ io.Writer) error {
+		return errBroken
+	})
+
+	srv := httptest.NewServer(NewHandler(f))
+	t.Cleanup(srv.Close)
+
+	resp := postNumbers(t, srv.URL, "[1, 2, 3]")
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
@@ -0,0 +1,123 @@
+//go:build inflight_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInFlightIdle(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 4, 4)
+	require.Empty(t, fact.InFlight())
+
+	err := fact.Factorize(context.Background(), generateNumbers(1000), newWriter())
+	require.NoError(t, err)
+
+	require.Empty(t, fact.InFlight())
+}
+
+func newStalledInFlightFactorizer(t *testing.T, factWorkers int, stalled ...int) *factorizerImpl {
+	t.Helper()
+
+	// a number counts as in flight from the moment a worker takes it, so
+	// stalling BeforeFactorize keeps it listed until cancellation
+	fact, err := New(
+		WithFactorizationWorkers(factWorkers),
+		WithWriteWorkers(1),
+		WithFaultInjector(stallOn(stalled...)),
+	)
+	require.NoError(t, err)
+
+	return fact
+}
+
+func TestInFlightReportsStalledNumber(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const stalledNumber = 1_000_003
+
+	fact := newStalledInFlightFactorizer(t, 2, stalledNumber)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	done := make(chan error)
+	go func() {
+		done <- fact.Factorize(ctx, []int{stalledNumber, 12, 25}, newWriter())
+	}()
+
+	var stalled InFlightInfo
+
+	require.Eventually(t, func() bool {
+		infos := fact.InFlight()
+
+		idx := slices.IndexFunc(infos, func(info InFlightInfo) bool {
+			return info.N == stalledNumber
+		})
+		if idx < 0 || infos[idx].Elapsed < time.Millisecond*100 {
+			return false
+		}
+
+		stalled = infos[idx]
+
+		return true
+	}, time.Second, time.Millisecond*10)
+
+	// elapsed time keeps growing while the number is still stalled
+	time.Sleep(time.Millisecond * 50)
+
+	infos := fact.InFlight()
+	require.Len(t, infos, 1)
+	require.Equal(t, stalledNumber, infos[0].N)
+	require.Greater(t, infos[0].Elapsed, stalled.Elapsed)
+
+	cancel()
+	require.ErrorIs(t, <-done, ErrFactorizationCancelled)
+
+	require.Empty(t, fact.InFlight())
+}
+
+func TestInFlightSlowestFirst(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	stalled := []int{101, 103, 107, 109}
+
+	fact := newStalledInFlightFactorizer(t, len(stalled), stalled...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	done := make(chan error)
+	go func() {
+		done <- fact.Factorize(ctx, stalled, newWriter())
+	}()
+
+	require.Eventually(t, func() bool {
+		return len(fact.InFlight()) == len(stalled)
+	}, time.Second, time.Millisecond*10)
+
+	for range 100 {
+		infos := fact.InFlight()
+		require.Len(t, infos, len(stalled))
+
+		require.True(t, slices.IsSortedFunc(infos, func(a, b InFlightInfo) int {
+			return int(b.Elapsed - a.Elapsed)
+		}), "in-flight numbers must be listed slowest first: %v", infos)
+
+		for _, info := range infos {
+			require.Contains(t, stalled, info.N)
+		}
+	}
+
+	cancel()
+	require.ErrorIs(t, <-done, ErrFactorizationCancelled)
+
+	require.Empty(t, fact.InFlight())
+}
@@ -0,0 +1,213 @@
+//go:build logger_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordingHandler struct {
+	level   slog.Level
+	mx      *sync.Mutex
+	records *[]slog.Record
+	attrs   []slog.Attr
+}
+
+func newRecordingHandler(level slog.Level) *recordingHandler {
+	return &recordingHandler{
+		level:   level,
+		mx:      new(sync.Mutex),
+		records: new([]slog.Record),
+	}
+}
+
+func (h *recordingHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *recordingHandler) Handle(_ context.Context, record slog.Record) error {
+	record = record.Clone()
+	record.AddAttrs(h.attrs...)
+
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	*h.records = append(*h.records, record)
+
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(slices.Clone(h.attrs), attrs...)
+
+	return &clone
+}
+
+func (h *recordingHandler) WithGroup(_ string) slog.Handler {
+	return h
+}
+
+func (h *recordingHandler) Records() []slog.Record {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	return slices.Clone(*h.records)
+}
+
+func countRecords(records []slog.Record, match func(slog.Record) bool) int {
+	count := 0
+	for _, record := range records {
+		if match(record) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func mentions(word string) func(slog.Record) bool {
+	return func(record slog.Record) bool {
+		return strings.Contains(strings.ToLower(record.Message), word)
+	}
+}
+
+func hasIntAttr(value int) func(slog.Record) bool {
+	return func(record slog.Record) bool {
+		found := false
+		record.Attrs(func(attr slog.Attr) bool {
+			v := attr.Value.Resolve()
+			found = v.Kind() == slog.KindInt64 && v.Int64() == int64(value)
+
+			return !found
+		})
+
+		return found
+	}
+}
+
+func TestLoggerInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithLogger(nil))
+
+	require.ErrorContains(t, err, "logger")
+}
+
+func TestLoggerLifecycle(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const (
+		factWorkers  = 3
+		writeWorkers = 5
+		count        = 1000
+	)
+
+	handler := newRecordingHandler(slog.LevelDebug)
+
+	fact, err := New(
+		WithFactorizationWorkers(factWorkers),
+		WithWriteWorkers(writeWorkers),
+		WithLogger(slog.New(handler)),
+	)
+	require.NoError(t, err)
+
+	err = fact.Factorize(context.Background(), generateNumbers(count), newWriter())
+	require.NoError(t, err)
+
+	records := handler.Records()
+
+	require.GreaterOrEqual(t, countRecords(records, mentions("start")), factWorkers+writeWorkers, "worker startup must be logged")
+	require.GreaterOrEqual(t, countRecords(records, mentions("stop")), factWorkers+writeWorkers, "worker shutdown must be logged")
+	require.Zero(t, countRecords(records, mentions("cancel")))
+	require.Zero(t, countRecords(records, func(record slog.Record) bool {
+		return record.Level >= slog.LevelWarn
+	}))
+
+	require.Equal(t, 1, countRecords(records, func(record slog.Record) bool {
+		return record.Level == slog.LevelInfo && hasIntAttr(count)(record)
+	}), "one summary per Factorize call")
+
+	// per-number logging would drown the summary
+	require.Less(t, len(records), count)
+}
+
+func TestLoggerLevels(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	handler := newRecordingHandler(slog.LevelWarn)
+
+	fact, err := New(
+		WithFactorizationWorkers(3),
+		WithWriteWorkers(3),
+		WithLogger(slog.New(handler)),
+	)
+	require.NoError(t, err)
+
+	err = fact.Factorize(context.Background(), generateNumbers(1000), newWriter())
+	require.NoError(t, err)
+
+	require.Empty(t, handler.Records(), "a successful run has nothing to warn about")
+}
+
+func TestLoggerWriterError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const n = 1073741789
+
+	handler := newRecordingHandler(slog.LevelInfo)
+
+	fact, err := New(
+		WithFactorizationWorkers(1),
+		WithWriteWorkers(1),
+		WithLogger(slog.New(handler)),
+	)
+	require.NoError(t, err)
+
+	errWrite := errors.New("disk full")
+
+	err = fact.Factorize(context.Background(), []int{n}, newSleepErrorWriter(0, errWrite))
+	require.ErrorIs(t, err, errWrite)
+
+	require.Equal(t, 1, countRecords(handler.Records(), func(record slog.Record) bool {
+		return record.Level == slog.LevelError && hasIntAttr(n)(record)
+	}), "writer error must be logged once with the number")
+}
+
+func TestLoggerCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	handler := newRecordingHandler(slog.LevelInfo)
+
+	fact, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithLogger(slog.New(handler)),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	err = fact.Factorize(ctx, generateNumbers(1_000_000), newSleepWriter(time.Millisecond))
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	records := handler.Records()
+	require.GreaterOrEqual(t, countRecords(records, mentions("cancel")), 1, "cancellation must be logged")
+	require.Zero(t, countRecords(records, func(record slog.Record) bool {
+		return record.Level >= slog.LevelError
+	}), "cancellation is not an error")
+
+	time.Sleep(time.Millisecond * 100)
+
+	require.Len(t, handler.Records(), len(records), "nothing must be logged after Factorize returns")
+}
@@ -0,0 +1,126 @@
+//go:build maxinflight_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// pendingWriter holds every line for a while and tracks the peak number of bytes
+// inside concurrent Write calls. Those bytes are formatted but not yet written.
+type pendingWriter struct {
+	*concurrentWriter
+
+	sleepTime   time.Duration
+	pending     atomic.Int64
+	peakPending atomic.Int64
+}
+
+func newPendingWriter(sleepTime time.Duration) *pendingWriter {
+	return &pendingWriter{
+		concurrentWriter: newWriter(),
+		sleepTime:        sleepTime,
+	}
+}
+
+func (p *pendingWriter) Write(b []byte) (n int, err error) {
+	pending := p.pending.Add(int64(len(b)))
+	defer p.pending.Add(-int64(len(b)))
+
+	for {
+		prev := p.peakPending.Load()
+		if pending <= prev || p.peakPending.CompareAndSwap(prev, pending) {
+			break
+		}
+	}
+
+	time.Sleep(p.sleepTime)
+
+	return p.concurrentWriter.Write(b)
+}
+
+func TestMaxInFlightBytesInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	for _, n := range []int{0, -100} {
+		_, err := New(WithMaxInFlightBytes(n))
+
+		require.ErrorContains(t, err, "in-flight")
+		require.ErrorContains(t, err, strconv.Itoa(n))
+	}
+}
+
+func TestMaxInFlightBytesBounded(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const budget = 600
+
+	// enough write workers to hold every result at once if nothing throttled dispatch
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(200),
+		WithMaxInFlightBytes(budget),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(5000)
+	writer := newPendingWriter(time.Millisecond)
+
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	require.Len(t, lines, len(numbers))
+
+	longest := 0
+	for _, line := range lines {
+		longest = max(longest, len(line)+1)
+	}
+
+	// only the result that crosses the budget may overshoot it
+	require.LessOrEqual(t, writer.peakPending.Load(), int64(budget+longest))
+}
+
+func TestMaxInFlightBytesLineLargerThanBudget(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(4),
+		WithMaxInFlightBytes(1),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(1000)
+	writer := newWriter()
+
+	// a single result over budget is still dispatched, one at a time
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	require.Len(t, getFact(writer), len(numbers))
+}
+
+func TestMaxInFlightBytesWriterError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	errWrite := errors.New("sink closed")
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(2),
+		WithMaxInFlightBytes(64),
+	)
+	require.NoError(t, err)
+
+	err = fact.Factorize(context.Background(), generateNumbers(10_000), newSleepErrorWriter(time.Millisecond, errWrite))
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errWrite)
+}
@@ -0,0 +1,176 @@
+//go:build maxworkers_test
+
+package fact
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxTotalWorkersInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	for _, n := range []int{0, -1} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			_, err := New(WithMaxTotalWorkers(n))
+
+			require.ErrorContains(t, err, "max total workers")
+			require.ErrorContains(t, err, strconv.Itoa(n))
+		})
+	}
+}
+
+func TestMaxTotalWorkersExceeded(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name string
+		opts []FactorizeOption
+	}{
+		{
+			name: "write workers",
+			opts: []FactorizeOption{
+				WithFactorizationWorkers(10),
+				WithWriteWorkers(100_000),
+				WithMaxTotalWorkers(1000),
+			},
+		},
+		{
+			name: "cap before workers",
+			opts: []FactorizeOption{
+				WithMaxTotalWorkers(1000),
+				WithFactorizationWorkers(100_000),
+				WithWriteWorkers(10),
+			},
+		},
+		{
+			name: "sum over cap",
+			opts: []FactorizeOption{
+				WithFactorizationWorkers(600),
+				WithWriteWorkers(401),
+				WithMaxTotalWorkers(1000),
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			fact, err := New(tt.opts...)
+
+			require.ErrorContains(t, err, "total workers")
+			require.ErrorContains(t, err, "1000")
+			require.Nil(t, fact)
+		})
+	}
+}
+
+func TestMaxTotalWorkersDefaults(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	// default worker counts are checked against the cap as well
+	_, err := New(WithMaxTotalWorkers(1))
+
+	require.ErrorContains(t, err, "total workers")
+}
+
+func TestMaxTotalWorkersWithinCap(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const (
+		factWorkers  = 10
+		writeWorkers = 20
+	)
+
+	fact, err := New(
+		WithFactorizationWorkers(factWorkers),
+		WithWriteWorkers(writeWorkers),
+		WithMaxTotalWorkers(factWorkers+writeWorkers),
+	)
+	require.NoError(t, err)
+
+	count := inspectNumGoroutines(t, func() {
+		err := fact.Factorize(context.Background(), generateNumbers(100), newSleepWriter(time.Millisecond*50))
+		require.NoError(t, err)
+	})
+
+	require.InDelta(t, factWorkers+writeWorkers, count, 3)
+}
+
+func TestMaxTotalWorkersUnsetIsUnbounded(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	// without the option the large counts used by the cancellation tests are accepted
+	_, err := New(
+		WithFactorizationWorkers(100_000),
+		WithWriteWorkers(100_000),
+	)
+	require.NoError(t, err)
+}
+
+func TestMaxTotalWorkersOverrides(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	newCapped := func(t *testing.T) *factorizerImpl {
+		t.Helper()
+
+		fact, err := New(
+			WithFactorizationWorkers(10),
+			WithWriteWorkers(10),
+			WithMaxTotalWorkers(100),
+		)
+		require.NoError(t, err)
+
+		return fact
+	}
+
+	testCases := []struct {
+		name string
+		opts []FactorizeOption
+		err  bool
+	}{
+		{name: "write workers over cap", opts: []FactorizeOption{WithWriteWorkers(1000)}, err: true},
+		{name: "sum over cap", opts: []FactorizeOption{WithFactorizationWorkers(50), WithWriteWorkers(51)}, err: true},
+		{name: "within cap", opts: []FactorizeOption{WithFactorizationWorkers(50), WithWriteWorkers(50)}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Run("per call", func(t *testing.T) {
+				writer := newWriter()
+				err := newCapped(t).FactorizeWith(context.Background(), generateNumbers(100), writer, tt.opts...)
+
+				if !tt.err {
+					require.NoError(t, err)
+					require.Len(t, getFact(writer), 100)
+
+					return
+				}
+
+				// the cap of the factorizer applies to per-call worker counts as well
+				require.ErrorContains(t, err, "total workers")
+				require.ErrorContains(t, err, "100")
+				require.Empty(t, writer.String())
+			})
+
+			t.Run("derived", func(t *testing.T) {
+				derived, err := newCapped(t).With(tt.opts...)
+
+				if !tt.err {
+					require.NoError(t, err)
+					require.NoError(t, derived.Factorize(context.Background(), generateNumbers(100), newWriter()))
+
+					return
+				}
+
+				// a derived factorizer inherits the cap
+				require.ErrorContains(t, err, "total workers")
+				require.ErrorContains(t, err, "100")
+				require.Nil(t, derived)
+			})
+		})
+	}
+}
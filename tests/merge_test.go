@@ -0,0 +1,160 @@
+//go:build merge_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readers(srcs ...string) []io.Reader {
+	res := make([]io.Reader, 0, len(srcs))
+	for _, src := range srcs {
+		res = append(res, strings.NewReader(src))
+	}
+
+	return res
+}
+
+func TestMergeOutputsGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name string
+		srcs []string
+		want string
+	}{
+		{
+			name: "no sources",
+			srcs: nil,
+			want: "",
+		},
+		{
+			name: "empty sources",
+			srcs: []string{"", ""},
+			want: "",
+		},
+		{
+			name: "single source",
+			srcs: []string{"100 = 2 * 2 * 5 * 5\n-17 = -1 * 17\n"},
+			want: "-17 = -1 * 17\n100 = 2 * 2 * 5 * 5\n",
+		},
+		{
+			name: "interleaved shards",
+			srcs: []string{
+				"6 = 2 * 3\n1 = 1\n",
+				"4 = 2 * 2\n0 = 0\n",
+				"5 = 5\n",
+			},
+			want: "0 = 0\n1 = 1\n4 = 2 * 2\n5 = 5\n6 = 2 * 3\n",
+		},
+		{
+			name: "repeated inputs",
+			srcs: []string{"4 = 2 * 2\n", "4 = 2 * 2\n4 = 2 * 2\n"},
+			want: "4 = 2 * 2\n4 = 2 * 2\n4 = 2 * 2\n",
+		},
+		{
+			name: "missing trailing newline",
+			srcs: []string{"9 = 3 * 3", "8 = 2 * 2 * 2"},
+			want: "8 = 2 * 2 * 2\n9 = 3 * 3\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := newWriter()
+			err := MergeOutputs(writer, readers(tt.srcs...)...)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.want, writer.String())
+		})
+	}
+}
+
+func TestMergeOutputsShards(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const shards = 4
+
+	numbers := append(generateNumbers(10_000), -1, -17)
+	fact := newFactorizer(t, 10, 10)
+
+	srcs := make([]io.Reader, 0, shards)
+	for part := range slices.Chunk(numbers, (len(numbers)+shards-1)/shards) {
+		writer := newWriter()
+		err := fact.Factorize(context.Background(), part, writer)
+		require.NoError(t, err)
+
+		srcs = append(srcs, strings.NewReader(writer.String()))
+	}
+
+	writer := newWriter()
+	err := MergeOutputs(writer, srcs...)
+	require.NoError(t, err)
+
+	allNums := make([]int, 0, len(numbers))
+	for _, line := range getFact(writer) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		allNums = append(allNums, num)
+	}
+
+	require.True(t, slices.IsSorted(allNums), "merged output must be sorted by n")
+
+	slices.Sort(numbers)
+	require.Equal(t, numbers, allNums)
+}
+
+func TestMergeOutputsInvalidLine(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name string
+		srcs []string
+	}{
+		{name: "malformed", srcs: []string{"4 = 2 * 2\n", "hello\n"}},
+		{name: "truncated", srcs: []string{"38 = 2 *"}},
+		{name: "wrong product", srcs: []string{"12 = 2 * 3\n"}},
+		{name: "composite factor", srcs: []string{"5 = 5\n", "12 = 2 * 6\n"}},
+		// valid lines for the same n always agree, so a disagreeing shard holds an invalid line
+		{name: "unfactored composite", srcs: []string{"15 = 3 * 5\n", "15 = 15\n"}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := MergeOutputs(newWriter(), readers(tt.srcs...)...)
+			require.Error(t, err)
+			require.NotErrorIs(t, err, ErrWriterInteraction)
+		})
+	}
+}
+
+func TestMergeOutputsReaderError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	errRead := errors.New("shard output unavailable")
+
+	writer := newWriter()
+	err := MergeOutputs(writer, strings.NewReader("1 = 1\n"), iotest.ErrReader(errRead))
+	require.ErrorIs(t, err, errRead)
+	require.NotErrorIs(t, err, ErrWriterInteraction)
+
+	require.Zero(t, len(writer.String()))
+}
+
+func TestMergeOutputsWriterError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	errWrite := errors.New("destination unavailable")
+
+	err := MergeOutputs(newSleepErrorWriter(0, errWrite), strings.NewReader("2 = 2\n3 = 3\n"))
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errWrite)
+}
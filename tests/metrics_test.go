@@ -0,0 +1,164 @@
+//go:build metrics_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var _ MetricsRecorder = (*fakeMetrics)(nil)
+
+type fakeMetrics struct {
+	mx           sync.Mutex
+	counters     map[string]int
+	observations map[string][]float64
+}
+
+func newFakeMetrics() *fakeMetrics {
+	return &fakeMetrics{
+		counters:     make(map[string]int),
+		observations: make(map[string][]float64),
+	}
+}
+
+func (m *fakeMetrics) Inc(name string) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	m.counters[name]++
+}
+
+func (m *fakeMetrics) Observe(name string, value float64) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	m.observations[name] = append(m.observations[name], value)
+}
+
+func (m *fakeMetrics) counter(name string) int {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	return m.counters[name]
+}
+
+func (m *fakeMetrics) observed(name string) []float64 {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	return slices.Clone(m.observations[name])
+}
+
+func TestMetricsInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithMetrics(nil))
+
+	require.ErrorContains(t, err, "metrics")
+}
+
+func TestMetricsRecorded(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	metrics := newFakeMetrics()
+
+	fact, err := New(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(10),
+		WithMetrics(metrics),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(10_000)
+
+	err = fact.Factorize(context.Background(), numbers, newWriter())
+	require.NoError(t, err)
+
+	for _, name := range []string{MetricDispatchLatency, MetricFactorizationDuration, MetricWriteDuration} {
+		values := metrics.observed(name)
+		require.Len(t, values, len(numbers), name)
+
+		for _, value := range values {
+			require.GreaterOrEqual(t, value, 0.0, name)
+		}
+	}
+
+	require.Zero(t, metrics.counter(MetricWriteErrors))
+}
+
+func TestMetricsDurationsInSeconds(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	metrics := newFakeMetrics()
+
+	fact, err := New(
+		WithFactorizationWorkers(1),
+		WithWriteWorkers(1),
+		WithMetrics(metrics),
+	)
+	require.NoError(t, err)
+
+	err = fact.Factorize(context.Background(), []int{1, 2, 3}, newSleepWriter(time.Millisecond*50))
+	require.NoError(t, err)
+
+	values := metrics.observed(MetricWriteDuration)
+	require.Len(t, values, 3)
+
+	for _, value := range values {
+		require.GreaterOrEqual(t, value, 0.05)
+		require.Less(t, value, 1.0)
+	}
+}
+
+func TestMetricsWriterError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	metrics := newFakeMetrics()
+
+	fact, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithMetrics(metrics),
+	)
+	require.NoError(t, err)
+
+	err = fact.Factorize(context.Background(), generateNumbers(1000), newSleepErrorWriter(time.Millisecond, errors.New("sink down")))
+	require.ErrorIs(t, err, ErrWriterInteraction)
+
+	require.GreaterOrEqual(t, metrics.counter(MetricWriteErrors), 1)
+	require.LessOrEqual(t, metrics.counter(MetricWriteErrors), 2)
+}
+
+func TestMetricsCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	metrics := newFakeMetrics()
+
+	fact, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithMetrics(metrics),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	err = fact.Factorize(ctx, generateNumbers(1_000_000), newSleepWriter(time.Millisecond))
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	written := len(metrics.observed(MetricWriteDuration))
+	require.Less(t, written, 1_000_000)
+	require.Zero(t, metrics.counter(MetricWriteErrors), "cancellation is not a writer error")
+
+	time.Sleep(time.Millisecond * 100)
+
+	require.Len(t, metrics.observed(MetricWriteDuration), written, "metrics must not be recorded after Factorize returns")
+}
@@ -0,0 +1,199 @@
+//go:build mobius_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func bruteMobius(n int) int {
+	n = max(n, -n)
+
+	mu := 1
+	for p := 2; p*p <= n; p++ {
+		if n%p != 0 {
+			continue
+		}
+
+		n /= p
+		if n%p == 0 {
+			return 0
+		}
+
+		mu = -mu
+	}
+
+	if n > 1 {
+		mu = -mu
+	}
+
+	return mu
+}
+
+func TestMobius(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		n          int
+		mu         int
+		squareFree bool
+	}{
+		{n: 1, mu: 1, squareFree: true},
+		{n: 2, mu: -1, squareFree: true},
+		{n: 6, mu: 1, squareFree: true},
+		{n: 30, mu: -1, squareFree: true},
+		{n: 4, mu: 0, squareFree: false},
+		{n: 12, mu: 0, squareFree: false},
+		{n: -30, mu: -1, squareFree: true},
+		{n: -1, mu: 1, squareFree: true},
+		{n: 97, mu: -1, squareFree: true},
+	}
+
+	fact := newFactorizer(t, 2, 2)
+
+	for _, tt := range testCases {
+		t.Run(strconv.Itoa(tt.n), func(t *testing.T) {
+			mu, err := fact.Mobius(context.Background(), tt.n)
+			require.NoError(t, err)
+			require.Equal(t, tt.mu, mu)
+
+			squareFree, err := fact.IsSquareFree(context.Background(), tt.n)
+			require.NoError(t, err)
+			require.Equal(t, tt.squareFree, squareFree)
+		})
+	}
+}
+
+func TestMobiusZero(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 1, 1)
+
+	// μ(0) is undefined, but every square divides zero
+	_, err := fact.Mobius(context.Background(), 0)
+	require.ErrorContains(t, err, "0")
+
+	squareFree, err := fact.IsSquareFree(context.Background(), 0)
+	require.NoError(t, err)
+	require.False(t, squareFree)
+}
+
+func TestMobiusCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fact := newFactorizer(t, 1, 1)
+
+	_, err := fact.Mobius(ctx, 30)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	_, err = fact.IsSquareFree(ctx, 30)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+}
+
+func TestMobiusAllGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := []int{1, 6, 12, -30, 97}
+
+	testCases := []struct {
+		name  string
+		batch func(*factorizerImpl, TestWriter) error
+		want  []string
+	}{
+		{
+			name: "mobius",
+			batch: func(f *factorizerImpl, w TestWriter) error {
+				return f.MobiusAll(context.Background(), numbers, w)
+			},
+			want: []string{"1: 1", "6: 1", "12: 0", "-30: -1", "97: -1"},
+		},
+		{
+			name: "square free",
+			batch: func(f *factorizerImpl, w TestWriter) error {
+				return f.SquareFreeAll(context.Background(), numbers, w)
+			},
+			want: []string{"1: true", "6: true", "12: false", "-30: true", "97: true"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := newWriter()
+			err := tt.batch(newFactorizer(t, 2, 2), writer)
+			require.NoError(t, err)
+
+			lines := getFact(writer)
+			slices.Sort(lines)
+			slices.Sort(tt.want)
+
+			require.Equal(t, tt.want, lines)
+		})
+	}
+}
+
+func TestMobiusAllCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := generateNumbers(10_000)[1:]
+	fact := newFactorizer(t, 8, 8)
+
+	writer := newWriter()
+	err := fact.MobiusAll(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	require.Len(t, lines, len(numbers))
+
+	for _, line := range lines {
+		n, mu := parseValueLine(t, line)
+		require.Equal(t, strconv.Itoa(bruteMobius(n)), mu, "line %q", line)
+	}
+
+	writer = newWriter()
+	err = fact.SquareFreeAll(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	lines = getFact(writer)
+	require.Len(t, lines, len(numbers))
+
+	for _, line := range lines {
+		n, squareFree := parseValueLine(t, line)
+		require.Equal(t, strconv.FormatBool(bruteMobius(n) != 0), squareFree, "line %q", line)
+	}
+}
+
+func TestMobiusAllErrors(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	t.Run("writer", func(t *testing.T) {
+		errWrite := errors.New("sink closed")
+
+		err := newFactorizer(t, 2, 2).MobiusAll(context.Background(), generateNumbers(1000)[1:], newSleepErrorWriter(time.Millisecond, errWrite))
+		require.ErrorIs(t, err, ErrWriterInteraction)
+		require.ErrorIs(t, err, errWrite)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+		t.Cleanup(cancel)
+
+		err := newFactorizer(t, 2, 2).SquareFreeAll(ctx, generateNumbers(1_000_000), newSleepWriter(time.Millisecond))
+		require.ErrorIs(t, err, ErrFactorizationCancelled)
+	})
+
+	t.Run("zero", func(t *testing.T) {
+		err := newFactorizer(t, 2, 2).MobiusAll(context.Background(), []int{4, 0, 9}, newWriter())
+		require.ErrorContains(t, err, "0")
+		require.NotErrorIs(t, err, ErrWriterInteraction)
+	})
+}
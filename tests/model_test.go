@@ -9,6 +9,7 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"io"
 	"math"
 	"math/rand/v2"
 	"path/filepath"
@@ -137,6 +138,25 @@ func TestCancelLow(t *testing.T) {
 	})
 }
 
+func TestCancelLongFactorization(t *testing.T) {
+	deferrableLeakDetection(t)
+	skipIfNot64Bit(t)
+
+	// hours of trial division and still seconds for rho, so nothing finishes before the deadline
+	numbers := slices.Repeat(hardSemiprimes, 2500)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	fact := newFactorizer(t, 4, 1)
+
+	start := time.Now()
+	err := fact.Factorize(ctx, numbers, newWriter())
+	require.Less(t, time.Since(start), time.Second)
+
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+}
+
 func TestInvalidOptions(t *testing.T) {
 	deferrableLeakDetection(t)
 
@@ -159,6 +179,23 @@ func TestInvalidOptions(t *testing.T) {
 		require.ErrorContains(t, err, "write")
 		require.ErrorContains(t, err, "-1")
 	})
+
+	t.Run("all violations", func(t *testing.T) {
+		_, err := New(
+			WithFactorizationWorkers(-2),
+			WithWriteWorkers(-3),
+		)
+
+		require.ErrorContains(t, err, "factorization")
+		require.ErrorContains(t, err, "-2")
+		require.ErrorContains(t, err, "write")
+		require.ErrorContains(t, err, "-3")
+
+		// violations are joined, so each one can be inspected on its own
+		joined, ok := err.(interface{ Unwrap() []error })
+		require.True(t, ok, "expected a joined error, got %T", err)
+		require.Len(t, joined.Unwrap(), 2)
+	})
 }
 
 func TestNoGoroutineLeak(t *testing.T) {
@@ -181,6 +218,82 @@ func TestNoGoroutineLeak(t *testing.T) {
 	require.LessOrEqual(t, runtime.NumGoroutine(), 3)
 }
 
+func TestCancellationCause(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	errCustom := errors.New("custom cause")
+
+	testCases := []struct {
+		name   string
+		ctx    func() (context.Context, context.CancelFunc)
+		writer io.Writer
+		cause  error
+	}{
+		{
+			name: "cancelled before start",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				return ctx, cancel
+			},
+			writer: newWriter(),
+			cause:  context.Canceled,
+		},
+		{
+			name: "custom cause before start",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancelCause(context.Background())
+				cancel(errCustom)
+
+				return ctx, func() { cancel(nil) }
+			},
+			writer: newWriter(),
+			cause:  errCustom,
+		},
+		{
+			name: "deadline while writing",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Millisecond*100)
+			},
+			writer: newSleepWriter(time.Millisecond),
+			cause:  context.DeadlineExceeded,
+		},
+		{
+			name: "custom cause while writing",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeoutCause(context.Background(), time.Millisecond*100, errCustom)
+			},
+			writer: newSleepWriter(time.Millisecond),
+			cause:  errCustom,
+		},
+		{
+			name: "cancel while writer is blocked",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancelCause(context.Background())
+				time.AfterFunc(time.Millisecond*100, func() { cancel(errCustom) })
+
+				return ctx, func() { cancel(nil) }
+			},
+			writer: newSleepWriter(time.Second),
+			cause:  errCustom,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			t.Cleanup(cancel)
+
+			fact := newFactorizer(t, 4, 1)
+
+			err := fact.Factorize(ctx, generateNumbers(1_000_000), tt.writer)
+			require.ErrorIs(t, err, ErrFactorizationCancelled)
+			require.ErrorIs(t, err, tt.cause)
+		})
+	}
+}
+
 func TestGoldenOutput(t *testing.T) {
 	deferrableLeakDetection(t)
 
@@ -255,6 +368,17 @@ func TestGoldenOutput(t *testing.T) {
 				"-20 = -1 * 2 * 2 * 5",
 			},
 		},
+		{
+			name:    "min int",
+			numbers: []int{math.MinInt},
+			opts: []FactorizeOption{
+				WithFactorizationWorkers(1),
+				WithWriteWorkers(1),
+			},
+			want: []string{
+				minIntLine(),
+			},
+		},
 		{
 			name:    "empty",
 			numbers: []int{},
@@ -392,6 +516,18 @@ func TestCorrectness(t *testing.T) {
 		input:        []int{144, 169, 196, 225, 256, 289, 324, 361, 400, 441, 484, 529},
 	}.Run(t)
 
+	TestFactorizationCorrectness{
+		factWorkers:  3,
+		writeWorkers: 3,
+		input:        []int{31, 37, 41, 43, 47, 49, 53, 59, 1147, 1763, 2491, 3599, 7429, 30030, 510510},
+	}.Run(t)
+
+	TestFactorizationCorrectness{
+		factWorkers:  2,
+		writeWorkers: 2,
+		input:        []int{121, 343, 1331, 2197, 863939, 289578289},
+	}.Run(t)
+
 	bigPrimeN := math.MaxInt32
 	if math.MaxInt == math.MaxInt64 {
 		bigPrimeN = 9223372036854775783
@@ -404,6 +540,29 @@ func TestCorrectness(t *testing.T) {
 	}.Run(t)
 }
 
+func TestCorrectness64Bit(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	TestFactorizationCorrectness{
+		factWorkers:  2,
+		writeWorkers: 2,
+		input:        []int{6469693230, 3909821048582988049},
+	}.Run(t)
+}
+
+// isQueueDepthBuffer reports whether the channel capacity comes from WithQueueDepth,
+// e.g. make(chan int, f.queueDepth), which is the only buffering allowed.
+func isQueueDepthBuffer(makeExpr *ast.CallExpr) bool {
+	if len(makeExpr.Args) != 2 {
+		return false
+	}
+
+	sel, ok := makeExpr.Args[1].(*ast.SelectorExpr)
+
+	return ok && sel.Sel.Name == "queueDepth"
+}
+
 func TestNoBufferedChannels(t *testing.T) {
 	deferrableLeakDetection(t)
 
@@ -422,7 +581,7 @@ func TestNoBufferedChannels(t *testing.T) {
 		ast.Inspect(node, func(n ast.Node) bool {
 			if makeExpr, ok := n.(*ast.CallExpr); ok {
 				if ident, ok := makeExpr.Fun.(*ast.Ident); ok && ident.Name == "make" {
-					if _, ok := makeExpr.Args[0].(*ast.ChanType); ok {
+					if _, ok := makeExpr.Args[0].(*ast.ChanType); ok && !isQueueDepthBuffer(makeExpr) {
 						require.Equal(t, 1, len(makeExpr.Args),
 							"File %s contains a buffered channel at position %v",
 							relPath, fset.Position(makeExpr.Pos()))
@@ -0,0 +1,191 @@
+//go:build narration_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func narrationLines(narration TestWriter) []string {
+	return slices.DeleteFunc(strings.Split(narration.String(), "\n"), func(line string) bool {
+		return line == ""
+	})
+}
+
+func findNarration(lines []string, words ...string) int {
+	return slices.IndexFunc(lines, func(line string) bool {
+		fields := strings.Fields(strings.ToLower(line))
+		for _, word := range words {
+			if !slices.ContainsFunc(fields, func(field string) bool {
+				return strings.HasPrefix(strings.Trim(field, ".,:;()[]#"), word)
+			}) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+func TestNarrationInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithTraceNarration(nil))
+
+	require.ErrorContains(t, err, "narration")
+}
+
+func TestNarrationLifecycle(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const (
+		factWorkers  = 3
+		writeWorkers = 2
+	)
+
+	narration := newWriter()
+
+	fact, err := New(
+		WithFactorizationWorkers(factWorkers),
+		WithWriteWorkers(writeWorkers),
+		WithTraceNarration(narration),
+	)
+	require.NoError(t, err)
+
+	numbers := []int{1000003, 1000033, 1000037, 1000039, 2000006}
+
+	writer := newWriter()
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+	require.Len(t, getFact(writer), len(numbers))
+
+	lines := narrationLines(narration)
+
+	started := 0
+	for _, line := range lines {
+		if findNarration([]string{line}, "start") >= 0 {
+			started++
+		}
+	}
+
+	require.GreaterOrEqual(t, started, factWorkers+writeWorkers, "every worker start must be narrated:\n%s", narration)
+
+	for _, n := range numbers {
+		num := strconv.Itoa(n)
+
+		dispatched := findNarration(lines, "dispatch", num)
+		written := findNarration(lines, "writ", num)
+
+		require.GreaterOrEqual(t, dispatched, 0, "dispatch of %d is not narrated:\n%s", n, narration)
+		require.GreaterOrEqual(t, written, 0, "write of %d is not narrated:\n%s", n, narration)
+		require.Less(t, dispatched, written, "write of %d is narrated before its dispatch:\n%s", n, narration)
+	}
+
+	require.Negative(t, findNarration(lines, "cancel"), "nothing was cancelled:\n%s", narration)
+}
+
+func TestNarrationCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	narration := newWriter()
+
+	fact, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithTraceNarration(narration),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	err = fact.Factorize(ctx, generateNumbers(1_000_000), newSleepWriter(time.Millisecond))
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	require.GreaterOrEqual(t, findNarration(narrationLines(narration), "cancel"), 0, "cancellation is not narrated:\n%s", narration)
+}
+
+func TestNarrationDetailLimit(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	narration := newWriter()
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(4),
+		WithTraceNarration(narration),
+	)
+	require.NoError(t, err)
+
+	// seven-digit numbers, so that no number is a prefix of another
+	numbers := make([]int, 0, NarrationDetailLimit+50)
+	for i := range NarrationDetailLimit + 50 {
+		numbers = append(numbers, 1_000_000+i)
+	}
+
+	err = fact.Factorize(context.Background(), numbers, newWriter())
+	require.NoError(t, err)
+
+	lines := narrationLines(narration)
+
+	// only the first NarrationDetailLimit dispatched numbers of a call are narrated one by one
+	for i, n := range numbers {
+		num := strconv.Itoa(n)
+
+		if i < NarrationDetailLimit {
+			require.GreaterOrEqual(t, findNarration(lines, "dispatch", num), 0, "dispatch of %d is not narrated:\n%s", n, narration)
+			require.GreaterOrEqual(t, findNarration(lines, "writ", num), 0, "write of %d is not narrated:\n%s", n, narration)
+
+			continue
+		}
+
+		require.Negative(t, findNarration(lines, "dispatch", num), "dispatch of %d is past the limit:\n%s", n, narration)
+		require.Negative(t, findNarration(lines, "writ", num), "write of %d is past the limit:\n%s", n, narration)
+	}
+}
+
+func TestNarrationLowVolume(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	narration := newWriter()
+
+	fact, err := New(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(10),
+		WithTraceNarration(narration),
+	)
+	require.NoError(t, err)
+
+	writer := newWriter()
+	err = fact.Factorize(context.Background(), generateNumbers(100_000), writer)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, narration.String())
+	require.Less(t, len(narration.String()), len(writer.String())/10, "narration must stay readable for large inputs")
+}
+
+func TestNarrationWriterErrorIgnored(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithTraceNarration(newSleepErrorWriter(0, errors.New("narration unavailable"))),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(1000)
+
+	writer := newWriter()
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+	require.Len(t, getFact(writer), len(numbers))
+}
@@ -0,0 +1,147 @@
+//go:build negativepolicy_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegativePolicyInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithNegativePolicy(NegativePolicy(100)))
+
+	require.ErrorContains(t, err, "negative policy")
+	require.ErrorContains(t, err, "100")
+}
+
+func TestNegativePolicyGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := []int{100, -17, 25, -38, -1, 0, 1}
+
+	testCases := []struct {
+		name   string
+		policy NegativePolicy
+		want   []string
+	}{
+		{
+			name:   "minus one",
+			policy: NegativeMinusOne,
+			want: []string{
+				"100 = 2 * 2 * 5 * 5",
+				"-17 = -1 * 17",
+				"25 = 5 * 5",
+				"-38 = -1 * 2 * 19",
+				"-1 = -1",
+				"0 = 0",
+				"1 = 1",
+			},
+		},
+		{
+			name:   "absolute",
+			policy: NegativeAbsolute,
+			want: []string{
+				"100 = 2 * 2 * 5 * 5",
+				"-17 = 17",
+				"25 = 5 * 5",
+				"-38 = 2 * 19",
+				"-1 = 1",
+				"0 = 0",
+				"1 = 1",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			fact, err := New(
+				WithFactorizationWorkers(3),
+				WithWriteWorkers(3),
+				WithNegativePolicy(tt.policy),
+			)
+			require.NoError(t, err)
+
+			writer := newWriter()
+			err = fact.Factorize(context.Background(), numbers, writer)
+			require.NoError(t, err)
+
+			facts := getFact(writer)
+			slices.Sort(facts)
+			slices.Sort(tt.want)
+
+			require.Equal(t, tt.want, facts)
+		})
+	}
+}
+
+func TestNegativePolicyDefaultIsMinusOne(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	writer := newWriter()
+	err := newFactorizer(t, 1, 1).Factorize(context.Background(), []int{-12}, writer)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"-12 = -1 * 2 * 2 * 3"}, getFact(writer))
+}
+
+func TestNegativePolicyReject(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name    string
+		numbers []int
+		want    int
+	}{
+		{name: "negative", numbers: []int{12, -17, 13}, want: -17},
+		{name: "minus one", numbers: []int{-1}, want: -1},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			fact, err := New(
+				WithFactorizationWorkers(1),
+				WithWriteWorkers(1),
+				WithNegativePolicy(NegativeReject),
+			)
+			require.NoError(t, err)
+
+			writer := newWriter()
+			err = fact.Factorize(context.Background(), tt.numbers, writer)
+
+			var negErr *NegativeInputError
+			require.True(t, errors.As(err, &negErr), "got %v", err)
+			require.Equal(t, tt.want, negErr.N)
+			require.NotErrorIs(t, err, ErrWriterInteraction)
+			require.NotErrorIs(t, err, ErrFactorizationCancelled)
+
+			for _, line := range getFact(writer) {
+				num, _ := parseLine(t, line)
+				require.GreaterOrEqual(t, num, 0, "rejected input must not be written")
+			}
+		})
+	}
+}
+
+func TestNegativePolicyRejectNonNegative(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(4),
+		WithNegativePolicy(NegativeReject),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(1000)
+
+	writer := newWriter()
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+	require.Len(t, getFact(writer), len(numbers))
+}
@@ -0,0 +1,149 @@
+//go:build nextprime_test
+
+package fact
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextPrevPrime(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 2, 2)
+
+	testCases := []struct {
+		n    int
+		next int
+		prev int
+	}{
+		{n: 3, next: 5, prev: 2},
+		{n: 4, next: 5, prev: 3},
+		{n: 24, next: 29, prev: 23},
+		{n: 97, next: 101, prev: 89},
+		{n: 1_000_000, next: 1_000_003, prev: 999_983},
+		{n: math.MaxInt32, next: 2147483659, prev: 2147483629},
+	}
+
+	for _, tt := range testCases {
+		t.Run(strconv.Itoa(tt.n), func(t *testing.T) {
+			prev, err := fact.PrevPrime(context.Background(), tt.n)
+			require.NoError(t, err)
+			require.Equal(t, tt.prev, prev)
+
+			next, err := fact.NextPrime(context.Background(), tt.n)
+			require.NoError(t, err)
+			require.Equal(t, tt.next, next)
+		})
+	}
+}
+
+func TestNextPrimeSmall(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 2, 2)
+
+	for _, n := range []int{math.MinInt, -17, -1, 0, 1} {
+		next, err := fact.NextPrime(context.Background(), n)
+		require.NoError(t, err)
+		require.Equal(t, 2, next, "n = %d", n)
+	}
+
+	next, err := fact.NextPrime(context.Background(), 2)
+	require.NoError(t, err)
+	require.Equal(t, 3, next)
+}
+
+func TestNextPrevPrimeOutOfRange(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 2, 2)
+
+	// there is no prime above the largest int64 prime
+	_, err := fact.NextPrime(context.Background(), 9223372036854775783)
+	require.ErrorContains(t, err, "9223372036854775783")
+
+	prev, err := fact.PrevPrime(context.Background(), 9223372036854775783)
+	require.NoError(t, err)
+	require.Equal(t, 9223372036854775643, prev)
+
+	_, err = fact.NextPrime(context.Background(), math.MaxInt)
+	require.Error(t, err)
+
+	for _, n := range []int{math.MinInt, -1, 0, 1, 2} {
+		_, err = fact.PrevPrime(context.Background(), n)
+		require.ErrorContains(t, err, strconv.Itoa(n))
+	}
+}
+
+func TestNextPrevPrimeMatchesTrialDivision(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 2, 2)
+
+	for n := 2; n <= 10_000; n++ {
+		next, err := fact.NextPrime(context.Background(), n)
+		require.NoError(t, err)
+		require.Greater(t, next, n)
+		require.True(t, pChecker.IsPrime(next))
+
+		for k := n + 1; k < next; k++ {
+			require.False(t, pChecker.IsPrime(k), "fact.NextPrime(%d) skipped %d", n, k)
+		}
+
+		if n == 2 {
+			continue
+		}
+
+		prev, err := fact.PrevPrime(context.Background(), n)
+		require.NoError(t, err)
+		require.Less(t, prev, n)
+		require.True(t, pChecker.IsPrime(prev))
+
+		for k := prev + 1; k < n; k++ {
+			require.False(t, pChecker.IsPrime(k), "fact.PrevPrime(%d) skipped %d", n, k)
+		}
+	}
+}
+
+func TestNextPrimeCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 2, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// cancellation is reported like the other Factorizer methods, with the cause kept
+	_, err := fact.NextPrime(ctx, 1<<62)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = fact.PrevPrime(ctx, 1<<62)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNextPrimePerformance(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 2, 2)
+
+	start := time.Now()
+
+	n := 1 << 62
+	for range 1000 {
+		next, err := fact.NextPrime(context.Background(), n)
+		require.NoError(t, err)
+
+		n = next
+	}
+
+	require.Less(t, time.Since(start), time.Second)
+}
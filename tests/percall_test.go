@@ -0,0 +1,116 @@
+//go:build percall_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFactorizeWithInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 2, 2)
+
+	writer := newWriter()
+	err := fact.FactorizeWith(context.Background(), generateNumbers(100), writer, WithWriteWorkers(-1))
+
+	require.ErrorContains(t, err, "write")
+	require.ErrorContains(t, err, "-1")
+	require.NotErrorIs(t, err, ErrWriterInteraction)
+	require.NotErrorIs(t, err, ErrFactorizationCancelled)
+	require.Empty(t, writer.String())
+}
+
+func TestFactorizeWithWorkerOverride(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const (
+		factWorkers  = 2
+		writeWorkers = 2
+		override     = 200
+	)
+
+	fact := newFactorizer(t, factWorkers, writeWorkers)
+
+	run := func(count int, opts ...FactorizeOption) int {
+		return inspectNumGoroutines(t, func() {
+			err := fact.FactorizeWith(context.Background(), generateNumbers(count), newSleepWriter(time.Millisecond*200), opts...)
+			require.NoError(t, err)
+		})
+	}
+
+	require.InDelta(t, factWorkers+override, run(2*override, WithWriteWorkers(override)), 20)
+
+	// overrides apply to a single call only
+	require.InDelta(t, factWorkers+writeWorkers, run(10), 3)
+}
+
+func TestFactorizeWithFormatOverride(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 2, 2)
+	numbers := []int{100, -17}
+
+	csv := newWriter()
+	err := fact.FactorizeWith(context.Background(), numbers, csv, WithCSVOutput(false))
+	require.NoError(t, err)
+
+	text := newWriter()
+	err = fact.Factorize(context.Background(), numbers, text)
+	require.NoError(t, err)
+
+	csvLines, textLines := getFact(csv), getFact(text)
+	slices.Sort(csvLines)
+	slices.Sort(textLines)
+
+	require.Equal(t, []string{"-17,-1;17", "100,2;2;5;5"}, csvLines)
+	require.Equal(t, []string{"-17 = -1 * 17", "100 = 2 * 2 * 5 * 5"}, textLines)
+}
+
+func TestFactorizeWithConcurrentCalls(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 4, 4)
+	numbers := generateNumbers(1000)
+
+	wg := new(sync.WaitGroup)
+	writers := make([]*concurrentWriter, 8)
+	errs := make([]error, len(writers))
+
+	for i := range writers {
+		writers[i] = newWriter()
+
+		wg.Go(func() {
+			var opts []FactorizeOption
+			if i%2 == 0 {
+				opts = append(opts, WithCSVOutput(false), WithFactorizationWorkers(i+1))
+			}
+
+			errs[i] = fact.FactorizeWith(context.Background(), numbers, writers[i], opts...)
+		})
+	}
+
+	wg.Wait()
+
+	// one call's overrides never leak into another running concurrently
+	for i, writer := range writers {
+		require.NoError(t, errs[i], "call %d", i)
+
+		lines := getFact(writer)
+		require.Len(t, lines, len(numbers))
+
+		for _, line := range lines {
+			if i%2 == 0 {
+				require.Contains(t, line, ",", "call %d", i)
+			} else {
+				require.Contains(t, line, " = ", "call %d", i)
+			}
+		}
+	}
+}
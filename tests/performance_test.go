@@ -4,6 +4,7 @@ package fact
 
 import (
 	"context"
+	"io"
 	"runtime"
 	"runtime/debug"
 	"testing"
@@ -101,3 +102,37 @@ func TestWorkersCount(t *testing.T) {
 
 	require.InDelta(t, gNum, factWorkers+writeWorkers, 50)
 }
+
+func TestAllocationsPerResult(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const (
+		small = 1_000
+		large = 11_000
+	)
+
+	allocs := func(n int) int64 {
+		fact := newFactorizer(t, runtime.GOMAXPROCS(-1), runtime.GOMAXPROCS(-1))
+		numbers := generateNumbers(n)
+
+		res := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+
+			for b.Loop() {
+				if err := fact.Factorize(context.Background(), numbers, io.Discard); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		// a failed benchmark reports zero iterations instead of failing t
+		require.Positive(t, res.N, "factorization failed inside the benchmark")
+
+		return res.AllocsPerOp()
+	}
+
+	// the per-call setup cost cancels out, leaving the cost of the extra results
+	perResult := float64(allocs(large)-allocs(small)) / float64(large-small)
+
+	require.LessOrEqual(t, perResult, 1.0)
+}
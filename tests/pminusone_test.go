@@ -0,0 +1,147 @@
+//go:build pminusone_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newPMinusOneFactorizer(t *testing.T, bound int) *factorizerImpl {
+	t.Helper()
+
+	fact, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithPollardPMinusOne(bound),
+	)
+	require.NoError(t, err)
+
+	return fact
+}
+
+func TestPMinusOneInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	for _, bound := range []int{1, 0, -10} {
+		_, err := New(WithPollardPMinusOne(bound))
+
+		require.ErrorContains(t, err, "p-1")
+		require.ErrorContains(t, err, strconv.Itoa(bound))
+	}
+}
+
+func TestPMinusOneGoldenOutput(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	// 2147483776 and 2147484222 are 1000-smooth; 2147483782 and 2147485246 are twice a prime
+	testCases := []struct {
+		name    string
+		numbers []int
+		want    []string
+	}{
+		{
+			name:    "one smooth factor",
+			numbers: []int{4611686585363088391, 4611690687057758081},
+			want: []string{
+				"4611686585363088391 = 2147483777 * 2147483783",
+				"4611690687057758081 = 2147484223 * 2147485247",
+			},
+		},
+		{
+			name:    "both factors smooth",
+			numbers: []int{4611687530255950271},
+			want: []string{
+				"4611687530255950271 = 2147483777 * 2147484223",
+			},
+		},
+		{
+			name:    "no smooth factor",
+			numbers: []int{4611689742164249401},
+			want: []string{
+				"4611689742164249401 = 2147483783 * 2147485247",
+			},
+		},
+		{
+			name:    "small numbers",
+			numbers: []int{100, -17, 0, 1, 2147483777},
+			want: []string{
+				"100 = 2 * 2 * 5 * 5",
+				"-17 = -1 * 17",
+				"0 = 0",
+				"1 = 1",
+				"2147483777 = 2147483777",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := newWriter()
+			err := newPMinusOneFactorizer(t, 1000).Factorize(context.Background(), tt.numbers, writer)
+			require.NoError(t, err)
+
+			facts := getFact(writer)
+			slices.Sort(facts)
+			slices.Sort(tt.want)
+
+			require.Equal(t, tt.want, facts)
+		})
+	}
+}
+
+func TestPMinusOneCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := generateNumbers(50_000)
+
+	writer := newWriter()
+	err := newPMinusOneFactorizer(t, 100).Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	allNums := make([]int, 0, len(numbers))
+	for _, line := range getFact(writer) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		allNums = append(allNums, num)
+	}
+
+	slices.Sort(allNums)
+	require.Equal(t, numbers, allNums)
+}
+
+func TestPMinusOnePerformance(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	numbers := slices.Repeat([]int{4611686585363088391, 4611690687057758081}, 50)
+
+	start := time.Now()
+
+	err := newPMinusOneFactorizer(t, 1000).Factorize(context.Background(), numbers, newWriter())
+	require.NoError(t, err)
+
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestPMinusOneCancel(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	start := time.Now()
+
+	// a huge bound keeps the stage busy, and no later stage can finish this many hard semiprimes
+	err := newPMinusOneFactorizer(t, 1<<40).Factorize(ctx, slices.Repeat(hardSemiprimes, 2500), newWriter())
+
+	require.Less(t, time.Since(start), time.Second)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+}
@@ -0,0 +1,87 @@
+//go:build pprof_test
+
+package fact
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"runtime/pprof"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var workerLabelRe = regexp.MustCompile(`"(fact_worker|write_worker)":"([^"]*)"`)
+
+// workerLabels returns the distinct worker ids found in the goroutine profile, keyed by label.
+func workerLabels(t *testing.T) map[string]map[string]struct{} {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, pprof.Lookup("goroutine").WriteTo(buf, 1))
+
+	res := map[string]map[string]struct{}{
+		"fact_worker":  {},
+		"write_worker": {},
+	}
+
+	for _, match := range workerLabelRe.FindAllStringSubmatch(buf.String(), -1) {
+		res[match[1]][match[2]] = struct{}{}
+	}
+
+	return res
+}
+
+func TestPprofWorkerLabels(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const (
+		factWorkers  = 3
+		writeWorkers = 7
+	)
+
+	fact := newFactorizer(t, factWorkers, writeWorkers)
+
+	var err error
+
+	wg := new(sync.WaitGroup)
+	wg.Go(func() {
+		err = fact.Factorize(context.Background(), generateNumbers(100), newSleepWriter(time.Millisecond*50))
+	})
+
+	time.Sleep(time.Millisecond * 100)
+
+	labels := workerLabels(t)
+
+	wg.Wait()
+
+	require.NoError(t, err)
+
+	require.Len(t, labels["fact_worker"], factWorkers)
+	require.Len(t, labels["write_worker"], writeWorkers)
+
+	after := workerLabels(t)
+	require.Empty(t, after["fact_worker"], "labelled goroutines must not outlive Factorize")
+	require.Empty(t, after["write_worker"], "labelled goroutines must not outlive Factorize")
+}
+
+func TestPprofCallerLabelsUntouched(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 2, 2)
+
+	pprof.Do(context.Background(), pprof.Labels("request", "42"), func(ctx context.Context) {
+		err := fact.Factorize(ctx, generateNumbers(100), newWriter())
+		require.NoError(t, err)
+
+		value, ok := pprof.Label(ctx, "request")
+		require.True(t, ok)
+		require.Equal(t, "42", value)
+
+		_, ok = pprof.Label(ctx, "fact_worker")
+		require.False(t, ok, "worker labels must not leak into the caller")
+	})
+}
@@ -0,0 +1,94 @@
+//go:build prefactor_test
+
+package fact
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newPrefactorService(t *testing.T, cache *mapCache, opts ...FactorizeOption) *Service {
+	t.Helper()
+
+	svc, err := NewService(append([]FactorizeOption{
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithFactorCache(cache),
+	}, opts...)...)
+	require.NoError(t, err)
+
+	// the background task must be stopped before leak detection runs
+	t.Cleanup(func() {
+		require.NoError(t, svc.Close())
+	})
+
+	return svc
+}
+
+// missRepeatedly factorizes numbers and evicts them afterwards, so that every call misses.
+func missRepeatedly(t *testing.T, svc *Service, cache *mapCache, numbers []int, times int) {
+	t.Helper()
+
+	for range times {
+		require.NoError(t, svc.Factorize(context.Background(), numbers, newWriter()))
+		cache.evict()
+	}
+}
+
+func TestPrefactorizationInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	for _, idle := range []time.Duration{0, -time.Second} {
+		_, err := NewService(WithIdlePrefactorization(idle))
+
+		require.ErrorContains(t, err, "idle")
+		require.ErrorContains(t, err, idle.String())
+	}
+}
+
+func TestPrefactorizationWarmsCache(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	cache := newMapCache()
+	svc := newPrefactorService(t, cache, WithIdlePrefactorization(time.Millisecond*50))
+
+	missRepeatedly(t, svc, cache, []int{1001, 1002}, 5)
+
+	_, _, puts := cache.stats()
+
+	// the popular values come back while the service is idle, without another Factorize call
+	require.Eventually(t, func() bool {
+		_, ok1001 := cache.peek(1001)
+		_, ok1002 := cache.peek(1002)
+
+		return ok1001 && ok1002
+	}, time.Second, time.Millisecond*10)
+
+	_, _, after := cache.stats()
+	require.Greater(t, after, puts)
+
+	f, _ := cache.peek(1001)
+	require.Equal(t, []int{7, 11, 13}, f)
+
+	f, _ = cache.peek(1002)
+	require.Equal(t, []int{2, 3, 167}, f)
+}
+
+func TestPrefactorizationDisabled(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	cache := newMapCache()
+	svc := newPrefactorService(t, cache)
+
+	missRepeatedly(t, svc, cache, []int{1001, 1002}, 5)
+
+	_, _, puts := cache.stats()
+
+	time.Sleep(time.Millisecond * 200)
+
+	_, _, after := cache.stats()
+	require.Equal(t, puts, after, "nothing is prefactorized without WithIdlePrefactorization")
+}
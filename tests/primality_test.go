@@ -0,0 +1,81 @@
+//go:build primality_test
+
+package fact
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPrime(t *testing.T) {
+	// the sign is ignored, as in TestPrimality and WithPrimalityAnnotation
+	testCases := []struct {
+		name string
+		n    int
+		want bool
+	}{
+		{name: "min int", n: math.MinInt, want: false},
+		{name: "negative prime", n: -17, want: true},
+		{name: "negative two", n: -2, want: true},
+		{name: "negative composite", n: -561, want: false},
+		{name: "minus one", n: -1, want: false},
+		{name: "zero", n: 0, want: false},
+		{name: "one", n: 1, want: false},
+		{name: "two", n: 2, want: true},
+		{name: "three", n: 3, want: true},
+		{name: "four", n: 4, want: false},
+		{name: "carmichael 561", n: 561, want: false},
+		{name: "carmichael 41041", n: 41041, want: false},
+		{name: "max int32", n: math.MaxInt32, want: true},
+		{name: "strong pseudoprime to bases 2, 3, 5, 7", n: 3215031751, want: false},
+		{name: "strong pseudoprime to bases up to 23", n: 3825123056546413051, want: false},
+		{name: "semiprime", n: 4611685975477714963, want: false},
+		{name: "big prime", n: 9223372036854775783, want: true},
+		{name: "max int", n: math.MaxInt64, want: false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, IsPrime(tt.n))
+		})
+	}
+}
+
+func TestIsPrimeMatchesTrialDivision(t *testing.T) {
+	for n := 2; n <= 100_000; n++ {
+		require.Equal(t, pChecker.IsPrime(n), IsPrime(n), "n = %d", n)
+		require.Equal(t, pChecker.IsPrime(n), IsPrime(-n), "n = %d", -n)
+	}
+}
+
+func TestIsPrimePerformance(t *testing.T) {
+	start := time.Now()
+
+	for range 10_000 {
+		require.True(t, IsPrime(9223372036854775783))
+	}
+
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestPrimeInputPerformance(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 1, 1)
+
+	writer := newWriter()
+	start := time.Now()
+
+	err := fact.Factorize(context.Background(), []int{9223372036854775783, 4611686018427387847}, writer)
+	require.NoError(t, err)
+
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+	require.ElementsMatch(t, []string{
+		"9223372036854775783 = 9223372036854775783",
+		"4611686018427387847 = 4611686018427387847",
+	}, getFact(writer))
+}
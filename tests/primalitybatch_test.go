@@ -0,0 +1,112 @@
+//go:build primalitybatch_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrimalityBatchGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	// the same rule as IsPrime and WithPrimalityAnnotation: the sign is ignored,
+	// ±1 are units, and zero is composite
+	numbers := []int{2, 17, 100, 561, math.MaxInt32, -17, -100, 0, 1, -1}
+	want := []string{
+		"2: prime",
+		"17: prime",
+		"100: composite",
+		"561: composite",
+		"2147483647: prime",
+		"-17: prime",
+		"-100: composite",
+		"0: composite",
+		"1: unit",
+		"-1: unit",
+	}
+
+	writer := newWriter()
+	err := newFactorizer(t, 3, 3).TestPrimality(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	slices.Sort(lines)
+	slices.Sort(want)
+
+	require.Equal(t, want, lines)
+}
+
+func TestPrimalityBatchMatchesIsPrime(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := make([]int, 0, 100_000)
+	for n := range 50_000 {
+		numbers = append(numbers, n, -n)
+	}
+
+	writer := newWriter()
+	err := newFactorizer(t, 8, 8).TestPrimality(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	require.Len(t, lines, len(numbers))
+
+	for _, line := range lines {
+		n, verdict := parseValueLine(t, line)
+
+		want := "composite"
+
+		switch {
+		case n == 1 || n == -1:
+			want = "unit"
+		case IsPrime(n):
+			want = "prime"
+		}
+
+		require.Equal(t, want, verdict, "n = %d", n)
+	}
+}
+
+func TestPrimalityBatchSkipsFactorization(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	// a verdict needs a few Miller–Rabin rounds, whatever the size of the factors
+	numbers := slices.Repeat(append(slices.Clone(hardSemiprimes), 9223372036854775783), 100)
+
+	start := time.Now()
+
+	writer := newWriter()
+	err := newFactorizer(t, 2, 2).TestPrimality(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	require.Less(t, time.Since(start), time.Second)
+	require.Len(t, getFact(writer), len(numbers))
+}
+
+func TestPrimalityBatchErrors(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	t.Run("writer", func(t *testing.T) {
+		errWrite := errors.New("sink closed")
+
+		err := newFactorizer(t, 2, 2).TestPrimality(context.Background(), generateNumbers(1000), newSleepErrorWriter(time.Millisecond, errWrite))
+		require.ErrorIs(t, err, ErrWriterInteraction)
+		require.ErrorIs(t, err, errWrite)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+		t.Cleanup(cancel)
+
+		err := newFactorizer(t, 2, 2).TestPrimality(ctx, generateNumbers(1_000_000), newSleepWriter(time.Millisecond))
+		require.ErrorIs(t, err, ErrFactorizationCancelled)
+	})
+}
@@ -0,0 +1,105 @@
+//go:build primepowers_test
+
+package fact
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// expandPowers flattens prime powers back into the Factorize factor list.
+func expandPowers(powers []PrimePower) []int {
+	factors := make([]int, 0, len(powers))
+	for _, pp := range powers {
+		for range pp.E {
+			factors = append(factors, pp.P)
+		}
+	}
+
+	return factors
+}
+
+func TestPrimePowersGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		n       int
+		factors []int
+		powers  []PrimePower
+	}{
+		{n: 100, factors: []int{2, 2, 5, 5}, powers: []PrimePower{{P: 2, E: 2}, {P: 5, E: 2}}},
+		{n: -17, factors: []int{-1, 17}, powers: []PrimePower{{P: -1, E: 1}, {P: 17, E: 1}}},
+		{n: 97, factors: []int{97}, powers: []PrimePower{{P: 97, E: 1}}},
+		{n: 1024, factors: slices.Repeat([]int{2}, 10), powers: []PrimePower{{P: 2, E: 10}}},
+		{n: -1, factors: []int{-1}, powers: []PrimePower{{P: -1, E: 1}}},
+		{n: 0, factors: []int{0}, powers: []PrimePower{{P: 0, E: 1}}},
+		{n: 1, factors: []int{1}, powers: []PrimePower{}},
+		{
+			n:       math.MinInt,
+			factors: append([]int{-1}, slices.Repeat([]int{2}, strconv.IntSize-1)...),
+			powers:  []PrimePower{{P: -1, E: 1}, {P: 2, E: strconv.IntSize - 1}},
+		},
+	}
+
+	fact := newFactorizer(t, 2, 2)
+
+	for _, tt := range testCases {
+		t.Run(strconv.Itoa(tt.n), func(t *testing.T) {
+			factors, err := fact.Factors(context.Background(), tt.n)
+			require.NoError(t, err)
+			require.Equal(t, tt.factors, factors)
+
+			powers, err := fact.PrimePowers(context.Background(), tt.n)
+			require.NoError(t, err)
+			require.Equal(t, tt.powers, powers)
+		})
+	}
+}
+
+func TestPrimePowersMatchFactors(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 1, 1)
+
+	for n := -5000; n <= 5000; n++ {
+		if n == 1 {
+			continue
+		}
+
+		factors, err := fact.Factors(context.Background(), n)
+		require.NoError(t, err)
+		require.True(t, checkFactorization(n, factors), "n = %d", n)
+
+		powers, err := fact.PrimePowers(context.Background(), n)
+		require.NoError(t, err)
+
+		// primes are strictly increasing, so every prime appears once
+		for i := 1; i < len(powers); i++ {
+			require.Less(t, powers[i-1].P, powers[i].P, "n = %d", n)
+		}
+
+		require.Equal(t, factors, expandPowers(powers), "n = %d", n)
+	}
+}
+
+func TestPrimePowersCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fact := newFactorizer(t, 1, 1)
+
+	factors, err := fact.Factors(ctx, 12)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+	require.Nil(t, factors)
+
+	powers, err := fact.PrimePowers(ctx, 12)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+	require.Nil(t, powers)
+}
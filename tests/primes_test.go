@@ -0,0 +1,117 @@
+//go:build primes_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func brutePrimes(lo, hi int) []int {
+	primes := make([]int, 0)
+	for n := max(lo, 2); n <= hi; n++ {
+		if pChecker.IsPrime(n) {
+			primes = append(primes, n)
+		}
+	}
+
+	return primes
+}
+
+func TestPrimesBetweenGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name   string
+		lo, hi int
+		want   []int
+	}{
+		{name: "small", lo: 0, hi: 30, want: []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}},
+		{name: "inclusive bounds", lo: 7, hi: 13, want: []int{7, 11, 13}},
+		{name: "negative lower bound", lo: -100, hi: 5, want: []int{2, 3, 5}},
+		{name: "no primes", lo: 24, hi: 28, want: nil},
+		{name: "empty range", lo: 30, hi: 10, want: nil},
+		{name: "single prime", lo: 97, hi: 97, want: []int{97}},
+	}
+
+	fact := newFactorizer(t, 2, 2)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slices.Collect(fact.PrimesBetween(context.Background(), tt.lo, tt.hi))
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPrimesBetweenSegments(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name   string
+		lo, hi int
+	}{
+		{name: "from zero", lo: 0, hi: 200_000},
+		{name: "far from zero", lo: 1_000_000_000_000, hi: 1_000_000_010_000},
+	}
+
+	fact := newFactorizer(t, 2, 2)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slices.Collect(fact.PrimesBetween(context.Background(), tt.lo, tt.hi))
+			require.Equal(t, brutePrimes(tt.lo, tt.hi), got)
+		})
+	}
+}
+
+func TestPrimesBetweenWithPrimeTable(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(WithPrimeTable(1000))
+	require.NoError(t, err)
+
+	// the range crosses the end of the shared table
+	got := slices.Collect(fact.PrimesBetween(context.Background(), 900, 1100))
+	require.Equal(t, brutePrimes(900, 1100), got)
+}
+
+func TestPrimesBetweenEarlyBreak(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 2, 2)
+
+	got := make([]int, 0, 5)
+	for p := range fact.PrimesBetween(context.Background(), 0, 1<<40) {
+		got = append(got, p)
+		if len(got) == 5 {
+			break
+		}
+	}
+
+	require.Equal(t, []int{2, 3, 5, 7, 11}, got)
+}
+
+func TestPrimesBetweenCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	fact := newFactorizer(t, 2, 2)
+
+	start := time.Now()
+
+	count := 0
+	for range fact.PrimesBetween(ctx, 0, 1<<40) {
+		count++
+	}
+
+	// the sequence ends once the context is done
+	require.Less(t, time.Since(start), time.Second)
+	require.Positive(t, count)
+}
@@ -0,0 +1,162 @@
+//go:build primetable_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrimeTableInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(
+		WithFactorizationWorkers(1),
+		WithWriteWorkers(1),
+		WithPrimeTable(-1),
+	)
+
+	require.ErrorContains(t, err, "prime table")
+	require.ErrorContains(t, err, "-1")
+}
+
+func TestPrimeTableGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name    string
+		limit   int
+		numbers []int
+		want    []string
+	}{
+		{
+			name:    "empty table",
+			limit:   0,
+			numbers: []int{100, -17},
+			want: []string{
+				"100 = 2 * 2 * 5 * 5",
+				"-17 = -1 * 17",
+			},
+		},
+		{
+			name:    "tiny table",
+			limit:   2,
+			numbers: []int{12, 9, 0, 1},
+			want: []string{
+				"12 = 2 * 2 * 3",
+				"9 = 3 * 3",
+				"0 = 0",
+				"1 = 1",
+			},
+		},
+		{
+			name:    "factors around the limit",
+			limit:   100,
+			numbers: []int{9409, 9797, 10201, 97, 101},
+			want: []string{
+				"9409 = 97 * 97",
+				"9797 = 97 * 101",
+				"10201 = 101 * 101",
+				"97 = 97",
+				"101 = 101",
+			},
+		},
+		{
+			name:    "limit is prime",
+			limit:   97,
+			numbers: []int{9409, 9797, -9797},
+			want: []string{
+				"9409 = 97 * 97",
+				"9797 = 97 * 101",
+				"-9797 = -1 * 97 * 101",
+			},
+		},
+		{
+			name:    "table covers sqrt",
+			limit:   1 << 16,
+			numbers: []int{2147395600, 4294836225, 65521},
+			want: []string{
+				"2147395600 = 2 * 2 * 2 * 2 * 5 * 5 * 7 * 7 * 331 * 331",
+				"4294836225 = 3 * 3 * 5 * 5 * 17 * 17 * 257 * 257",
+				"65521 = 65521",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			fact, err := New(
+				WithFactorizationWorkers(2),
+				WithWriteWorkers(2),
+				WithPrimeTable(tt.limit),
+			)
+			require.NoError(t, err)
+
+			writer := newWriter()
+			err = fact.Factorize(context.Background(), tt.numbers, writer)
+			require.NoError(t, err)
+
+			facts := getFact(writer)
+			slices.Sort(tt.want)
+			slices.Sort(facts)
+
+			require.Equal(t, tt.want, facts)
+		})
+	}
+}
+
+func TestPrimeTableIsLazy(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	start := time.Now()
+
+	_, err := New(WithPrimeTable(1 << 28))
+	require.NoError(t, err)
+
+	require.Less(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestPrimeTableConcurrentCalls(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(10),
+		WithPrimeTable(1<<20),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(10_000)
+	writers := make([]*concurrentWriter, 10)
+	errs := make([]error, len(writers))
+
+	wg := new(sync.WaitGroup)
+	for i := range writers {
+		writers[i] = newWriter()
+
+		wg.Go(func() {
+			errs[i] = fact.Factorize(context.Background(), numbers, writers[i])
+		})
+	}
+
+	wg.Wait()
+
+	for i, writer := range writers {
+		require.NoError(t, errs[i])
+
+		allNums := make([]int, 0, len(numbers))
+		for _, line := range getFact(writer) {
+			num, res := parseLine(t, line)
+			require.True(t, checkFactorization(num, res))
+			allNums = append(allNums, num)
+		}
+
+		slices.Sort(allNums)
+		require.Equal(t, numbers, allNums)
+	}
+}
@@ -0,0 +1,114 @@
+//go:build priority_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithPriorityFunc(nil))
+
+	require.ErrorContains(t, err, "priority")
+}
+
+func TestPriorityCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(10),
+		WithPriorityFunc(func(n int) int { return n % 7 }),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(100_000)
+	writer := newWriter()
+
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	allNums := make([]int, 0, len(numbers))
+	for _, line := range getFact(writer) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		allNums = append(allNums, num)
+	}
+
+	slices.Sort(allNums)
+	require.Equal(t, numbers, allNums)
+}
+
+func TestPriorityUrgentFirst(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const (
+		bulk   = 200
+		urgent = 5
+	)
+
+	fact, err := New(
+		WithFactorizationWorkers(1),
+		WithWriteWorkers(1),
+		WithPriorityFunc(func(n int) int {
+			if n < 0 {
+				return 10
+			}
+
+			return 0
+		}),
+	)
+	require.NoError(t, err)
+
+	// urgent numbers come last in the input
+	numbers := generateNumbers(bulk)
+	for i := range urgent {
+		numbers = append(numbers, -(i + 1))
+	}
+
+	writer := newSleepWriter(time.Millisecond)
+
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	require.Len(t, lines, len(numbers))
+
+	// only numbers already taken by the workers may overtake urgent ones
+	const slack = 10
+
+	for i, line := range lines {
+		num, _ := parseLine(t, line)
+		if num < 0 {
+			require.Less(t, i, urgent+slack, "urgent number %d written at position %d", num, i)
+		}
+	}
+}
+
+func TestPriorityCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithPriorityFunc(func(n int) int { return -n }),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	start := time.Now()
+
+	err = fact.Factorize(ctx, generateNumbers(1_000_000), newSleepWriter(time.Millisecond))
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	require.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,157 @@
+//go:build progress_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type progressRecorder struct {
+	mx      sync.Mutex
+	running atomic.Bool
+	overlap atomic.Bool
+	done    []int
+	totals  []int
+	delay   time.Duration
+}
+
+func (p *progressRecorder) report(done, total int) {
+	if !p.running.CompareAndSwap(false, true) {
+		p.overlap.Store(true)
+	}
+	defer p.running.Store(false)
+
+	time.Sleep(p.delay)
+
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	p.done = append(p.done, done)
+	p.totals = append(p.totals, total)
+}
+
+func (p *progressRecorder) calls() ([]int, []int) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	return slices.Clone(p.done), slices.Clone(p.totals)
+}
+
+func TestProgressInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithProgress(nil))
+
+	require.ErrorContains(t, err, "progress")
+}
+
+func TestProgressReportsCompletion(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name         string
+		count        int
+		factWorkers  int
+		writeWorkers int
+	}{
+		{name: "single", count: 1, factWorkers: 1, writeWorkers: 1},
+		{name: "sequential", count: 100, factWorkers: 1, writeWorkers: 1},
+		{name: "parallel", count: 100_000, factWorkers: 10, writeWorkers: 10},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := new(progressRecorder)
+
+			fact, err := New(
+				WithFactorizationWorkers(tt.factWorkers),
+				WithWriteWorkers(tt.writeWorkers),
+				WithProgress(rec.report),
+			)
+			require.NoError(t, err)
+
+			writer := newWriter()
+			err = fact.Factorize(context.Background(), generateNumbers(tt.count), writer)
+			require.NoError(t, err)
+
+			done, totals := rec.calls()
+			require.NotEmpty(t, done)
+			require.False(t, rec.overlap.Load(), "progress callback must not be called concurrently")
+
+			for i := range done {
+				require.Equal(t, tt.count, totals[i])
+				require.LessOrEqual(t, done[i], tt.count)
+
+				if i > 0 {
+					require.GreaterOrEqual(t, done[i], done[i-1], "progress must not go backwards")
+				}
+			}
+
+			require.Equal(t, tt.count, done[len(done)-1], "final progress must be reported before Factorize returns")
+		})
+	}
+}
+
+func TestProgressSlowCallbackDoesNotBlock(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	rec := &progressRecorder{delay: time.Millisecond * 50}
+
+	fact, err := New(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(10),
+		WithProgress(rec.report),
+	)
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	writer := newWriter()
+	err = fact.Factorize(context.Background(), generateNumbers(100_000), writer)
+	require.NoError(t, err)
+
+	// one callback per number would take more than an hour
+	require.Less(t, time.Since(start), time.Second*5)
+
+	done, _ := rec.calls()
+	require.Equal(t, 100_000, done[len(done)-1])
+	require.Len(t, getFact(writer), 100_000)
+}
+
+func TestProgressCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	rec := new(progressRecorder)
+
+	fact, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithProgress(rec.report),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	const count = 10_000_000
+
+	err = fact.Factorize(ctx, generateNumbers(count), newWriter())
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	done, _ := rec.calls()
+	if len(done) > 0 {
+		require.Less(t, done[len(done)-1], count)
+	}
+
+	time.Sleep(time.Millisecond * 100)
+
+	after, _ := rec.calls()
+	require.Len(t, after, len(done), "progress must not be reported after Factorize returns")
+}
@@ -0,0 +1,89 @@
+//go:build queuedepth_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueDepthInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithQueueDepth(-1))
+
+	require.ErrorContains(t, err, "queue depth")
+	require.ErrorContains(t, err, "-1")
+}
+
+func TestQueueDepthCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	for _, depth := range []int{0, 1, 16, 1024} {
+		fact, err := New(
+			WithFactorizationWorkers(4),
+			WithWriteWorkers(4),
+			WithQueueDepth(depth),
+		)
+		require.NoError(t, err)
+
+		numbers := generateNumbers(10_000)
+		writer := newWriter()
+
+		err = fact.Factorize(context.Background(), numbers, writer)
+		require.NoError(t, err)
+
+		allNums := make([]int, 0, len(numbers))
+		for _, line := range getFact(writer) {
+			num, res := parseLine(t, line)
+			require.True(t, checkFactorization(num, res))
+			allNums = append(allNums, num)
+		}
+
+		slices.Sort(allNums)
+		require.Equal(t, numbers, allNums, "depth %d", depth)
+	}
+}
+
+func TestQueueDepthCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(1),
+		WithQueueDepth(1000),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	start := time.Now()
+
+	err = fact.Factorize(ctx, generateNumbers(1_000_000), newSleepWriter(time.Millisecond*10))
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestQueueDepthWriterError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(10),
+		WithQueueDepth(1000),
+	)
+	require.NoError(t, err)
+
+	errWrite := errors.New("sink closed")
+
+	err = fact.Factorize(context.Background(), generateNumbers(1_000_000), newSleepErrorWriter(time.Millisecond, errWrite))
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errWrite)
+}
@@ -0,0 +1,145 @@
+//go:build radical_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func bruteRadical(n int) int {
+	n = max(n, -n)
+
+	rad := 1
+	for p := 2; p*p <= n; p++ {
+		if n%p != 0 {
+			continue
+		}
+
+		rad *= p
+		for n%p == 0 {
+			n /= p
+		}
+	}
+
+	if n > 1 {
+		rad *= n
+	}
+
+	return rad
+}
+
+func TestRadical(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		n    int
+		want int
+	}{
+		{n: 1, want: 1},
+		{n: -1, want: 1},
+		{n: 12, want: 6},
+		{n: -12, want: 6},
+		{n: 97, want: 97},
+		{n: 1024, want: 2},
+		{n: 1_000_000, want: 10},
+		{n: math.MinInt, want: 2},
+		{n: math.MaxInt32, want: math.MaxInt32},
+	}
+
+	fact := newFactorizer(t, 2, 2)
+
+	for _, tt := range testCases {
+		t.Run(strconv.Itoa(tt.n), func(t *testing.T) {
+			got, err := fact.Radical(context.Background(), tt.n)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRadicalZero(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	// zero has no finite set of prime divisors
+	_, err := newFactorizer(t, 1, 1).Radical(context.Background(), 0)
+	require.ErrorContains(t, err, "0")
+}
+
+func TestRadicalCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := newFactorizer(t, 1, 1).Radical(ctx, 12)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+}
+
+func TestRadicalAllGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := []int{1, 12, -12, 97, 1024}
+	want := []string{"1: 1", "12: 6", "-12: 6", "97: 97", "1024: 2"}
+
+	writer := newWriter()
+	err := newFactorizer(t, 2, 2).RadicalAll(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	slices.Sort(lines)
+	slices.Sort(want)
+
+	require.Equal(t, want, lines)
+}
+
+func TestRadicalAllCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := generateNumbers(20_000)[1:]
+
+	writer := newWriter()
+	err := newFactorizer(t, 8, 8).RadicalAll(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	require.Len(t, lines, len(numbers))
+
+	for _, line := range lines {
+		n, got := parseValueLine(t, line)
+		require.Equal(t, strconv.Itoa(bruteRadical(n)), got, "line %q", line)
+	}
+}
+
+func TestRadicalAllErrors(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	t.Run("zero", func(t *testing.T) {
+		err := newFactorizer(t, 2, 2).RadicalAll(context.Background(), []int{4, 0, 9}, newWriter())
+		require.ErrorContains(t, err, "0")
+		require.NotErrorIs(t, err, ErrWriterInteraction)
+	})
+
+	t.Run("writer", func(t *testing.T) {
+		errWrite := errors.New("sink closed")
+
+		err := newFactorizer(t, 2, 2).RadicalAll(context.Background(), generateNumbers(1000)[1:], newSleepErrorWriter(time.Millisecond, errWrite))
+		require.ErrorIs(t, err, ErrWriterInteraction)
+		require.ErrorIs(t, err, errWrite)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+		t.Cleanup(cancel)
+
+		err := newFactorizer(t, 2, 2).RadicalAll(ctx, generateNumbers(1_000_000)[1:], newSleepWriter(time.Millisecond))
+		require.ErrorIs(t, err, ErrFactorizationCancelled)
+	})
+}
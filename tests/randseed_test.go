@@ -0,0 +1,137 @@
+//go:build randseed_test
+
+package fact
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandSeedConflictsWithRandSource(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	// a shared source cannot provide independent per-worker streams
+	_, err := New(
+		WithRandSeed(42),
+		WithRandSource(rand.NewPCG(1, 2)),
+	)
+	require.ErrorContains(t, err, "seed")
+	require.ErrorContains(t, err, "rand source")
+}
+
+func TestRandSeedCorrectness(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	numbers := slices.Repeat(hardSemiprimes, 50)
+
+	for _, seed := range []uint64{0, 1, 42, 1 << 63} {
+		t.Run(strconv.FormatUint(seed, 10), func(t *testing.T) {
+			fact, err := New(
+				WithFactorizationWorkers(8),
+				WithWriteWorkers(2),
+				WithRandSeed(seed),
+			)
+			require.NoError(t, err)
+
+			writer := newWriter()
+			err = fact.Factorize(context.Background(), numbers, writer)
+			require.NoError(t, err)
+
+			lines := getFact(writer)
+			require.Len(t, lines, len(numbers))
+
+			for _, line := range lines {
+				num, res := parseLine(t, line)
+				require.True(t, checkFactorization(num, res))
+			}
+		})
+	}
+}
+
+// rhoIterations factorizes numbers once and returns the rho iterations the call used.
+func rhoIterations(t *testing.T, fact *factorizerImpl, numbers []int) int {
+	t.Helper()
+
+	before := fact.RhoStats().Iterations
+	require.NoError(t, fact.Factorize(context.Background(), numbers, newWriter()))
+
+	return fact.RhoStats().Iterations - before
+}
+
+func TestRandSeedReproducible(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	// a single worker consumes its stream in input order, so the iteration count depends only on the seed
+	run := func(seed uint64) int {
+		fact, err := New(
+			WithFactorizationWorkers(1),
+			WithWriteWorkers(1),
+			WithRandSeed(seed),
+		)
+		require.NoError(t, err)
+
+		iterations := rhoIterations(t, fact, hardSemiprimes)
+		require.Positive(t, iterations)
+
+		return iterations
+	}
+
+	require.Equal(t, run(7), run(7))
+
+	seen := make(map[int]bool)
+	for seed := range uint64(8) {
+		seen[run(seed)] = true
+	}
+
+	require.Greater(t, len(seen), 1, "the seed must change the random stream")
+}
+
+func TestRandSeedStreamPerCall(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(1),
+		WithWriteWorkers(1),
+		WithRandSeed(7),
+	)
+	require.NoError(t, err)
+
+	require.Zero(t, fact.RhoStats())
+
+	// every call restarts the streams from the seed instead of continuing them
+	first := rhoIterations(t, fact, hardSemiprimes)
+	require.Equal(t, first, rhoIterations(t, fact, hardSemiprimes))
+	require.Equal(t, RhoStats{Iterations: 2 * first}, fact.RhoStats())
+}
+
+func TestRandSeedReusedFactorizer(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(4),
+		WithRandSeed(42),
+	)
+	require.NoError(t, err)
+
+	// streams are derived per call, so concurrent calls do not share state
+	done := make(chan error)
+	for range 4 {
+		go func() {
+			done <- fact.Factorize(context.Background(), hardSemiprimes, newWriter())
+		}()
+	}
+
+	for range 4 {
+		require.NoError(t, <-done)
+	}
+}
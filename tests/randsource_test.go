@@ -0,0 +1,106 @@
+//go:build randsource_test
+
+package fact
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type countingSource struct {
+	src        rand.Source
+	calls      atomic.Int64
+	inUse      atomic.Bool
+	concurrent atomic.Bool
+}
+
+func newCountingSource(seed uint64) *countingSource {
+	return &countingSource{
+		src: rand.NewPCG(seed, seed),
+	}
+}
+
+func (c *countingSource) Uint64() uint64 {
+	if !c.inUse.CompareAndSwap(false, true) {
+		c.concurrent.Store(true)
+	}
+	defer c.inUse.Store(false)
+
+	c.calls.Add(1)
+
+	return c.src.Uint64()
+}
+
+func TestRandSourceInvalid(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithRandSource(nil))
+	require.ErrorContains(t, err, "rand source")
+}
+
+func TestRandSourceUsedByRho(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	src := newCountingSource(42)
+
+	fact, err := New(
+		WithFactorizationWorkers(8),
+		WithWriteWorkers(8),
+		WithRandSource(src),
+	)
+	require.NoError(t, err)
+
+	numbers := make([]int, 0, 100*len(hardSemiprimes))
+	for range 100 {
+		numbers = append(numbers, hardSemiprimes...)
+	}
+
+	writer := newWriter()
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	for _, line := range getFact(writer) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+	}
+
+	require.Positive(t, src.calls.Load())
+	require.False(t, src.concurrent.Load(), "rand source must not be used concurrently")
+}
+
+func TestRandSourceFixedSeed(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	run := func(seed uint64) (string, int64) {
+		src := newCountingSource(seed)
+
+		fact, err := New(
+			WithFactorizationWorkers(1),
+			WithWriteWorkers(1),
+			WithRandSource(src),
+		)
+		require.NoError(t, err)
+
+		writer := newWriter()
+		require.NoError(t, fact.Factorize(context.Background(), hardSemiprimes, writer))
+
+		facts := getFact(writer)
+		slices.Sort(facts)
+
+		return strings.Join(facts, "\n"), src.calls.Load()
+	}
+
+	firstOut, firstCalls := run(7)
+	secondOut, secondCalls := run(7)
+
+	require.Equal(t, firstOut, secondOut)
+	require.Equal(t, firstCalls, secondCalls)
+}
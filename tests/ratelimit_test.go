@@ -0,0 +1,140 @@
+//go:build ratelimit_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type timestampWriter struct {
+	*concurrentWriter
+	mx    *sync.Mutex
+	times []time.Time
+}
+
+func newTimestampWriter() *timestampWriter {
+	return &timestampWriter{
+		concurrentWriter: newWriter(),
+		mx:               new(sync.Mutex),
+	}
+}
+
+func (w *timestampWriter) Write(p []byte) (n int, err error) {
+	w.mx.Lock()
+	w.times = append(w.times, time.Now())
+	w.mx.Unlock()
+
+	return w.concurrentWriter.Write(p)
+}
+
+func (w *timestampWriter) Times() []time.Time {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	return slices.Clone(w.times)
+}
+
+func TestWriteRateLimitInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name      string
+		perSecond int
+		want      string
+	}{
+		{name: "zero", perSecond: 0, want: "0"},
+		{name: "negative", perSecond: -5, want: "-5"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(WithWriteRateLimit(tt.perSecond))
+
+			require.ErrorContains(t, err, "rate")
+			require.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestWriteRateLimitShared(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const (
+		perSecond = 100
+		count     = 300
+	)
+
+	fact, err := New(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(50),
+		WithWriteRateLimit(perSecond),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(count)
+	writer := newTimestampWriter()
+
+	start := time.Now()
+
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	// the limit is shared by all write workers; allow a burst of up to one second worth of tokens
+	elapsed := time.Since(start)
+	require.GreaterOrEqual(t, elapsed, time.Second*(count-perSecond)/perSecond)
+	require.Less(t, elapsed, time.Second*(count/perSecond+2))
+
+	times := writer.Times()
+	require.Len(t, times, count)
+
+	slices.SortFunc(times, func(a, b time.Time) int { return a.Compare(b) })
+
+	for i := range times {
+		j := i
+		for j < len(times) && times[j].Sub(times[i]) < time.Second {
+			j++
+		}
+
+		require.LessOrEqual(t, j-i, 2*perSecond, "too many writes within a second starting at write %d", i)
+	}
+
+	allNums := make([]int, 0, len(numbers))
+	for _, line := range getFact(writer) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		allNums = append(allNums, num)
+	}
+
+	slices.Sort(allNums)
+	require.Equal(t, numbers, allNums)
+}
+
+func TestWriteRateLimitCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithWriteRateLimit(1),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	start := time.Now()
+
+	writer := newWriter()
+	err = fact.Factorize(ctx, generateNumbers(100), writer)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	// waiting for a token must be interruptible
+	require.Less(t, time.Since(start), time.Second)
+	require.LessOrEqual(t, len(getFact(writer)), 2)
+}
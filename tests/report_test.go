@@ -0,0 +1,118 @@
+//go:build report_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// failAfterWriter accepts the first limit writes and fails every write after them.
+type failAfterWriter struct {
+	*concurrentWriter
+	writes atomic.Int64
+	limit  int64
+	err    error
+}
+
+func (f *failAfterWriter) Write(p []byte) (n int, err error) {
+	if f.writes.Add(1) > f.limit {
+		return 0, f.err
+	}
+
+	return f.concurrentWriter.Write(p)
+}
+
+func TestReportComplete(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := generateNumbers(10_000)
+	writer := newWriter()
+
+	report, err := newFactorizer(t, 4, 4).FactorizeWithReport(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	require.Equal(t, Report{Written: len(numbers), Factorized: len(numbers)}, report)
+	require.Len(t, getFact(writer), report.Written)
+}
+
+func TestReportEmptyInput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	report, err := newFactorizer(t, 1, 1).FactorizeWithReport(context.Background(), nil, newWriter())
+	require.NoError(t, err)
+
+	require.Zero(t, report)
+}
+
+func TestReportCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name string
+		ctx  func() (context.Context, context.CancelFunc)
+	}{
+		{
+			name: "before start",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				return ctx, cancel
+			},
+		},
+		{
+			name: "while writing",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Millisecond*100)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			t.Cleanup(cancel)
+
+			numbers := generateNumbers(100_000)
+			writer := newSleepWriter(time.Millisecond)
+
+			report, err := newFactorizer(t, 4, 2).FactorizeWithReport(ctx, numbers, writer)
+			require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+			require.Len(t, getFact(writer), report.Written)
+			require.LessOrEqual(t, report.Written, report.Factorized)
+			require.Equal(t, len(numbers), report.Factorized+report.Skipped)
+			require.Positive(t, report.Skipped)
+		})
+	}
+}
+
+func TestReportWriterError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const limit = 50
+
+	errWrite := errors.New("disk full")
+	writer := &failAfterWriter{
+		concurrentWriter: newWriter(),
+		limit:            limit,
+		err:              errWrite,
+	}
+
+	numbers := generateNumbers(10_000)
+
+	report, err := newFactorizer(t, 4, 4).FactorizeWithReport(context.Background(), numbers, writer)
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errWrite)
+
+	// a failed write is not counted as written
+	require.Equal(t, limit, report.Written)
+	require.Len(t, getFact(writer), limit)
+	require.Equal(t, len(numbers), report.Factorized+report.Skipped)
+}
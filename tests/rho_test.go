@@ -0,0 +1,119 @@
+//go:build rho_test
+
+package fact
+
+import (
+	"context"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRhoGoldenOutput(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name    string
+		numbers []int
+		want    []string
+	}{
+		{
+			name:    "semiprimes",
+			numbers: hardSemiprimes,
+			want: []string{
+				"1152921423002469787 = 1073741783 * 1073741789",
+				"4611685975477714963 = 2147483629 * 2147483647",
+				"9223372021822390277 = 2147483647 * 4294967291",
+				"999999866000004473 = 999999929 * 999999937",
+			},
+		},
+		{
+			name:    "prime square",
+			numbers: []int{1152921429444920521},
+			want: []string{
+				"1152921429444920521 = 1073741789 * 1073741789",
+			},
+		},
+		{
+			name:    "small and large factor",
+			numbers: []int{281479271350267, -2305842920093122601},
+			want: []string{
+				"281479271350267 = 65537 * 4294967291",
+				"-2305842920093122601 = -1 * 1073741783 * 2147483647",
+			},
+		},
+		{
+			name:    "max int",
+			numbers: []int{math.MaxInt64},
+			want: []string{
+				"9223372036854775807 = 7 * 7 * 73 * 127 * 337 * 92737 * 649657",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			fact := newFactorizer(t, 2, 2)
+
+			writer := newWriter()
+			err := fact.Factorize(context.Background(), tt.numbers, writer)
+			require.NoError(t, err)
+
+			facts := getFact(writer)
+			slices.Sort(tt.want)
+			slices.Sort(facts)
+
+			require.Equal(t, tt.want, facts)
+		})
+	}
+}
+
+func TestRhoPerformance(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	numbers := make([]int, 0, 25*len(hardSemiprimes))
+	for range 25 {
+		numbers = append(numbers, hardSemiprimes...)
+	}
+
+	fact := newFactorizer(t, 1, 1)
+	writer := newWriter()
+
+	start := time.Now()
+	err := fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	// plain trial division needs about a second per number here
+	require.Less(t, time.Since(start), 5*time.Second)
+
+	for _, line := range getFact(writer) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+	}
+}
+
+func TestRhoCancel(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	numbers := make([]int, 0, 10_000*len(hardSemiprimes))
+	for range 10_000 {
+		numbers = append(numbers, hardSemiprimes...)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	t.Cleanup(cancel)
+
+	fact := newFactorizer(t, 4, 4)
+
+	start := time.Now()
+	err := fact.Factorize(ctx, numbers, newWriter())
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	require.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,186 @@
+//go:build router_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime/debug"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func routedNumbers(t *testing.T, writer TestWriter) []int {
+	t.Helper()
+
+	res := make([]int, 0)
+	for _, line := range getFact(writer) {
+		num, factors := parseLine(t, line)
+		require.True(t, checkFactorization(num, factors))
+		res = append(res, num)
+	}
+
+	slices.Sort(res)
+
+	return res
+}
+
+func TestWriterRouterInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithWriterRouter(nil))
+
+	require.ErrorContains(t, err, "router")
+}
+
+func TestWriterRouterPrimesAndComposites(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := generateNumbers(10_000)
+
+	// the router runs on write workers, so it only reads a set computed up front:
+	// primeChecker memoizes into an unguarded map
+	pc := newPrimeChecker()
+	isPrime := make(map[int]bool, len(numbers))
+
+	var wantPrimes, wantComposites []int
+	for _, n := range numbers {
+		isPrime[n] = pc.IsPrime(n)
+
+		if isPrime[n] {
+			wantPrimes = append(wantPrimes, n)
+		} else {
+			wantComposites = append(wantComposites, n)
+		}
+	}
+
+	primes, composites := newWriter(), newWriter()
+
+	fact, err := New(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(10),
+		WithWriterRouter(func(n int) io.Writer {
+			if isPrime[n] {
+				return primes
+			}
+
+			return composites
+		}),
+	)
+	require.NoError(t, err)
+
+	fallback := newWriter()
+
+	err = fact.Factorize(context.Background(), numbers, fallback)
+	require.NoError(t, err)
+
+	require.Equal(t, wantPrimes, routedNumbers(t, primes))
+	require.Equal(t, wantComposites, routedNumbers(t, composites))
+	require.Empty(t, getFact(fallback))
+}
+
+func TestWriterRouterFallback(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	negatives := newWriter()
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(4),
+		WithWriterRouter(func(n int) io.Writer {
+			if n < 0 {
+				return negatives
+			}
+
+			// nil routes to the writer passed to Factorize
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	fallback := newWriter()
+
+	err = fact.Factorize(context.Background(), []int{100, -17, 25, -38, 38}, fallback)
+	require.NoError(t, err)
+
+	require.Equal(t, []int{-38, -17}, routedNumbers(t, negatives))
+	require.Equal(t, []int{25, 38, 100}, routedNumbers(t, fallback))
+}
+
+func TestWriterRouterSharedPool(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const (
+		buckets      = 100
+		factWorkers  = 4
+		writeWorkers = 4
+	)
+
+	writers := make([]*concurrentWriter, buckets)
+	for i := range writers {
+		writers[i] = newWriter()
+	}
+
+	fact, err := New(
+		WithFactorizationWorkers(factWorkers),
+		WithWriteWorkers(writeWorkers),
+		WithWriterRouter(func(n int) io.Writer {
+			return writers[n%buckets]
+		}),
+	)
+	require.NoError(t, err)
+
+	debug.SetGCPercent(-1)
+	t.Cleanup(func() {
+		debug.SetGCPercent(100)
+	})
+
+	numbers := generateNumbers(10_000)
+
+	gNum := inspectNumGoroutines(t, func() {
+		err := fact.Factorize(context.Background(), numbers, newWriter())
+		require.NoError(t, err)
+	})
+
+	// destinations must not get write workers of their own
+	require.LessOrEqual(t, gNum, factWorkers+writeWorkers+50)
+
+	total := 0
+	for i, writer := range writers {
+		for _, n := range routedNumbers(t, writer) {
+			require.Equal(t, i, n%buckets)
+		}
+
+		total += len(getFact(writer))
+	}
+
+	require.Equal(t, len(numbers), total)
+}
+
+func TestWriterRouterWriterError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	errWrite := errors.New("shard unavailable")
+	broken := newSleepErrorWriter(time.Millisecond, errWrite)
+
+	fact, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithWriterRouter(func(n int) io.Writer {
+			if n%2 == 0 {
+				return broken
+			}
+
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	err = fact.Factorize(context.Background(), generateNumbers(1000), newWriter())
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errWrite)
+}
@@ -0,0 +1,152 @@
+//go:build semiprime_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func bruteSemiprime(n int) bool {
+	n = max(n, -n)
+
+	count := 0
+	for p := 2; p*p <= n; p++ {
+		for n%p == 0 {
+			n /= p
+			count++
+		}
+	}
+
+	if n > 1 {
+		count++
+	}
+
+	return count == 2
+}
+
+func TestIsSemiprime(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		n    int
+		want bool
+	}{
+		{n: 0, want: false},
+		{n: 1, want: false},
+		{n: 2, want: false},
+		{n: 4, want: true},
+		{n: 6, want: true},
+		{n: -6, want: true},
+		{n: 8, want: false},
+		{n: 12, want: false},
+		{n: 49, want: true},
+		{n: 97, want: false},
+		{n: math.MaxInt32, want: false},
+	}
+
+	fact := newFactorizer(t, 2, 2)
+
+	for _, tt := range testCases {
+		t.Run(strconv.Itoa(tt.n), func(t *testing.T) {
+			got, err := fact.IsSemiprime(context.Background(), tt.n)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsSemiprimeHard(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 1, 1)
+
+	start := time.Now()
+
+	for _, n := range hardSemiprimes {
+		got, err := fact.IsSemiprime(context.Background(), n)
+		require.NoError(t, err)
+		require.True(t, got, "n = %d", n)
+	}
+
+	for _, n := range []int{9223372036854775783, math.MaxInt, hardSemiprimes[0] * 2} {
+		got, err := fact.IsSemiprime(context.Background(), n)
+		require.NoError(t, err)
+		require.False(t, got, "n = %d", n)
+	}
+
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestIsSemiprimeCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := newFactorizer(t, 1, 1).IsSemiprime(ctx, 6)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+}
+
+func TestSemiprimeAllGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := []int{4, 6, -6, 8, 97, 1}
+	want := []string{"4: true", "6: true", "-6: true", "8: false", "97: false", "1: false"}
+
+	writer := newWriter()
+	err := newFactorizer(t, 2, 2).SemiprimeAll(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	slices.Sort(lines)
+	slices.Sort(want)
+
+	require.Equal(t, want, lines)
+}
+
+func TestSemiprimeAllCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := generateNumbers(20_000)
+
+	writer := newWriter()
+	err := newFactorizer(t, 8, 8).SemiprimeAll(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	require.Len(t, lines, len(numbers))
+
+	for _, line := range lines {
+		n, got := parseValueLine(t, line)
+		require.Equal(t, strconv.FormatBool(bruteSemiprime(n)), got, "line %q", line)
+	}
+}
+
+func TestSemiprimeAllErrors(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	t.Run("writer", func(t *testing.T) {
+		errWrite := errors.New("sink closed")
+
+		err := newFactorizer(t, 2, 2).SemiprimeAll(context.Background(), generateNumbers(1000), newSleepErrorWriter(time.Millisecond, errWrite))
+		require.ErrorIs(t, err, ErrWriterInteraction)
+		require.ErrorIs(t, err, errWrite)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+		t.Cleanup(cancel)
+
+		err := newFactorizer(t, 2, 2).SemiprimeAll(ctx, generateNumbers(1_000_000), newSleepWriter(time.Millisecond))
+		require.ErrorIs(t, err, ErrFactorizationCancelled)
+	})
+}
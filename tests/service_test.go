@@ -0,0 +1,155 @@
+//go:build service_test
+
+package fact
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var _ Factorizer = (*Service)(nil)
+
+// firstWriteWriter closes started on its first Write.
+type firstWriteWriter struct {
+	io.Writer
+	once    sync.Once
+	started chan struct{}
+}
+
+func newFirstWriteWriter(w io.Writer) *firstWriteWriter {
+	return &firstWriteWriter{
+		Writer:  w,
+		started: make(chan struct{}),
+	}
+}
+
+func (f *firstWriteWriter) Write(p []byte) (int, error) {
+	f.once.Do(func() {
+		close(f.started)
+	})
+
+	return f.Writer.Write(p)
+}
+
+func TestServiceInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := NewService(WithFactorizationWorkers(-1))
+
+	require.ErrorContains(t, err, "factorization")
+	require.ErrorContains(t, err, "-1")
+}
+
+func TestServiceFactorize(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	svc, err := NewService(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+	)
+	require.NoError(t, err)
+
+	writer := newWriter()
+	err = svc.Factorize(context.Background(), []int{100, -17}, writer)
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{"100 = 2 * 2 * 5 * 5", "-17 = -1 * 17"}, getFact(writer))
+}
+
+func TestServiceReloadAppliesToNewJobs(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	svc, err := NewService(
+		WithFactorizationWorkers(1),
+		WithWriteWorkers(1),
+	)
+	require.NoError(t, err)
+
+	err = svc.Reload(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(100),
+	)
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	err = svc.Factorize(context.Background(), generateNumbers(100), newSleepWriter(time.Millisecond*100))
+	require.NoError(t, err)
+
+	// a single write worker would need 10 seconds
+	require.Less(t, time.Since(start), time.Second*2)
+}
+
+func TestServiceReloadKeepsRunningJobs(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	svc, err := NewService(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(100),
+	)
+	require.NoError(t, err)
+
+	var (
+		jobErr     error
+		jobElapsed time.Duration
+	)
+
+	writer := newFirstWriteWriter(newSleepWriter(time.Millisecond * 100))
+
+	wg := new(sync.WaitGroup)
+	wg.Go(func() {
+		start := time.Now()
+
+		jobErr = svc.Factorize(context.Background(), generateNumbers(100), writer)
+		jobElapsed = time.Since(start)
+	})
+
+	// reload only once the job is running on the old configuration
+	<-writer.started
+
+	err = svc.Reload(
+		WithFactorizationWorkers(1),
+		WithWriteWorkers(1),
+	)
+	require.NoError(t, err)
+
+	wg.Wait()
+
+	require.NoError(t, jobErr)
+
+	// the job must not be downgraded to a single write worker halfway through
+	require.Less(t, jobElapsed, time.Second*2)
+
+	start := time.Now()
+
+	err = svc.Factorize(context.Background(), generateNumbers(10), newSleepWriter(time.Millisecond*100))
+	require.NoError(t, err)
+
+	require.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestServiceReloadInvalidKeepsConfig(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	svc, err := NewService(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(100),
+	)
+	require.NoError(t, err)
+
+	err = svc.Reload(WithWriteWorkers(-1))
+	require.ErrorContains(t, err, "write")
+	require.ErrorContains(t, err, "-1")
+
+	start := time.Now()
+
+	err = svc.Factorize(context.Background(), generateNumbers(100), newSleepWriter(time.Millisecond*100))
+	require.NoError(t, err)
+
+	require.Less(t, time.Since(start), time.Second*2)
+}
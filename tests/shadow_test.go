@@ -0,0 +1,246 @@
+//go:build shadow_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type factorizerFunc func(ctx context.Context, numbers []int, writer io.Writer) error
+
+func (f factorizerFunc) Factorize(ctx context.Context, numbers []int, writer io.Writer) error {
+	return f(ctx, numbers, writer)
+}
+
+// shadowResults is a concurrency-safe reporter: shadows finish on their own goroutines.
+type shadowResults struct {
+	mx      sync.Mutex
+	results []ShadowResult
+}
+
+func (s *shadowResults) report(res ShadowResult) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.results = append(s.results, res)
+}
+
+func (s *shadowResults) get() []ShadowResult {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return slices.Clone(s.results)
+}
+
+func TestShadowMatchingCandidate(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	collected := new(shadowResults)
+
+	shadow := NewShadow(
+		newFactorizer(t, 2, 2),
+		newFactorizer(t, 4, 4),
+		WithShadowPercent(100),
+		WithShadowReporter(collected.report),
+	)
+
+	numbers := []int{100, -17, 25, 38}
+	writer := newWriter()
+
+	err := shadow.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	facts := getFact(writer)
+	slices.Sort(facts)
+	require.Equal(t, []string{
+		"-17 = -1 * 17",
+		"100 = 2 * 2 * 5 * 5",
+		"25 = 5 * 5",
+		"38 = 2 * 19",
+	}, facts)
+
+	shadow.Wait()
+
+	results := collected.get()
+	require.Len(t, results, 1)
+	require.False(t, results[0].Diverged)
+	require.Empty(t, results[0].Missing)
+	require.Empty(t, results[0].Unexpected)
+	require.NoError(t, results[0].Err)
+	require.Positive(t, results[0].PrimaryDuration)
+	require.Positive(t, results[0].ShadowDuration)
+}
+
+func TestShadowDivergence(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	candidate := factorizerFunc(func(_ context.Context, numbers []int, writer io.Writer) error {
+		for _, n := range numbers {
+			if _, err := io.WriteString(writer, strconv.Itoa(n)+" = "+strconv.Itoa(n)+"\n"); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	collected := new(shadowResults)
+
+	shadow := NewShadow(
+		newFactorizer(t, 1, 1),
+		candidate,
+		WithShadowPercent(100),
+		WithShadowReporter(collected.report),
+	)
+
+	err := shadow.Factorize(context.Background(), []int{5, 6}, newWriter())
+	require.NoError(t, err)
+
+	shadow.Wait()
+
+	results := collected.get()
+	require.Len(t, results, 1)
+	require.True(t, results[0].Diverged)
+	require.Equal(t, []string{"6 = 2 * 3"}, results[0].Missing)
+	require.Equal(t, []string{"6 = 6"}, results[0].Unexpected)
+}
+
+func TestShadowCandidateErrorIsIsolated(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	errCandidate := errors.New("candidate failed")
+	candidate := factorizerFunc(func(context.Context, []int, io.Writer) error {
+		return errCandidate
+	})
+
+	collected := new(shadowResults)
+
+	shadow := NewShadow(
+		newFactorizer(t, 1, 1),
+		candidate,
+		WithShadowPercent(100),
+		WithShadowReporter(collected.report),
+	)
+
+	writer := newWriter()
+	err := shadow.Factorize(context.Background(), []int{4}, writer)
+	require.NoError(t, err)
+	require.Equal(t, []string{"4 = 2 * 2"}, getFact(writer))
+
+	shadow.Wait()
+
+	results := collected.get()
+	require.Len(t, results, 1)
+	require.ErrorIs(t, results[0].Err, errCandidate)
+	require.True(t, results[0].Diverged)
+}
+
+func TestShadowPrimaryError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	errWrite := errors.New("primary sink failed")
+
+	shadow := NewShadow(
+		newFactorizer(t, 1, 1),
+		newFactorizer(t, 1, 1),
+		WithShadowPercent(100),
+	)
+
+	err := shadow.Factorize(context.Background(), []int{4}, newSleepErrorWriter(time.Millisecond, errWrite))
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errWrite)
+
+	shadow.Wait()
+}
+
+func TestShadowDoesNotDelayPrimary(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const candidateTime = time.Millisecond * 500
+
+	candidate := factorizerFunc(func(ctx context.Context, numbers []int, writer io.Writer) error {
+		time.Sleep(candidateTime)
+
+		return newFactorizer(t, 1, 1).Factorize(ctx, numbers, writer)
+	})
+
+	collected := new(shadowResults)
+
+	shadow := NewShadow(
+		newFactorizer(t, 1, 1),
+		candidate,
+		WithShadowPercent(100),
+		WithShadowReporter(collected.report),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	start := time.Now()
+
+	writer := newWriter()
+	require.NoError(t, shadow.Factorize(ctx, []int{4}, writer))
+	require.Less(t, time.Since(start), candidateTime/2, "the primary must not wait for the candidate")
+	require.Equal(t, []string{"4 = 2 * 2"}, getFact(writer))
+
+	// the caller's context usually ends with the request; the shadow keeps running
+	cancel()
+
+	require.Empty(t, collected.get())
+
+	shadow.Wait()
+
+	results := collected.get()
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+	require.False(t, results[0].Diverged)
+	require.GreaterOrEqual(t, results[0].ShadowDuration, candidateTime)
+}
+
+func TestShadowPercent(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name    string
+		percent int
+		min     int64
+		max     int64
+	}{
+		{name: "none", percent: 0, min: 0, max: 0},
+		{name: "half", percent: 50, min: 400, max: 600},
+		{name: "all", percent: 100, min: 1000, max: 1000},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := atomic.Int64{}
+
+			shadow := NewShadow(
+				newFactorizer(t, 1, 1),
+				newFactorizer(t, 1, 1),
+				WithShadowPercent(tt.percent),
+				WithShadowReporter(func(ShadowResult) {
+					calls.Add(1)
+				}),
+			)
+
+			for range 1000 {
+				require.NoError(t, shadow.Factorize(context.Background(), []int{1}, newWriter()))
+			}
+
+			shadow.Wait()
+
+			require.GreaterOrEqual(t, calls.Load(), tt.min)
+			require.LessOrEqual(t, calls.Load(), tt.max)
+		})
+	}
+}
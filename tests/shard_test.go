@@ -0,0 +1,80 @@
+//go:build shard_test
+
+package fact
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardInput(t *testing.T) {
+	testCases := []struct {
+		name   string
+		nums   []int
+		shards int
+	}{
+		{name: "even", nums: generateNumbers(100), shards: 4},
+		{name: "uneven", nums: generateNumbers(101), shards: 7},
+		{name: "single shard", nums: generateNumbers(10), shards: 1},
+		{name: "more shards than numbers", nums: []int{1, 2, 3}, shards: 8},
+		{name: "duplicates", nums: []int{4, 4, 4, -17, -17, 0}, shards: 3},
+		{name: "empty", nums: []int{}, shards: 5},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			input := slices.Clone(tt.nums)
+
+			all := make([]int, 0, len(tt.nums))
+			minLen, maxLen := math.MaxInt, 0
+
+			for shard := range tt.shards {
+				part := ShardInput(tt.nums, tt.shards, shard)
+				require.Equal(t, part, ShardInput(tt.nums, tt.shards, shard), "sharding must be deterministic")
+
+				all = append(all, part...)
+				minLen = min(minLen, len(part))
+				maxLen = max(maxLen, len(part))
+			}
+
+			require.Equal(t, tt.nums, all, "shards must be disjoint and cover the input in order")
+			require.LessOrEqual(t, maxLen-minLen, 1)
+			require.Equal(t, input, tt.nums, "input must not be modified")
+		})
+	}
+}
+
+func TestShardInputInvalid(t *testing.T) {
+	nums := generateNumbers(10)
+
+	require.Panics(t, func() { ShardInput(nums, 0, 0) })
+	require.Panics(t, func() { ShardInput(nums, -1, 0) })
+	require.Panics(t, func() { ShardInput(nums, 3, -1) })
+	require.Panics(t, func() { ShardInput(nums, 3, 3) })
+}
+
+func TestShardFor(t *testing.T) {
+	const shards = 8
+
+	counts := make([]int, shards)
+	numbers := append(generateNumbers(100_000), -1, -17, math.MinInt, math.MaxInt)
+
+	for _, n := range numbers {
+		shard := ShardFor(n, shards)
+		require.GreaterOrEqual(t, shard, 0)
+		require.Less(t, shard, shards)
+		require.Equal(t, shard, ShardFor(n, shards), "sharding must be deterministic")
+
+		counts[shard]++
+	}
+
+	for shard, count := range counts {
+		require.InDelta(t, len(numbers)/shards, count, float64(len(numbers)/shards)*0.2, "shard %d", shard)
+	}
+
+	require.Zero(t, ShardFor(42, 1))
+	require.Panics(t, func() { ShardFor(42, 0) })
+}
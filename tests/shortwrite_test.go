@@ -0,0 +1,126 @@
+//go:build shortwrite_test
+
+package fact
+
+import (
+	"context"
+	"io"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShortWriteRetriesRemainder(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name    string
+		limit   int
+		numbers []int
+		want    []string
+	}{
+		{
+			name:    "half line",
+			limit:   4,
+			numbers: []int{100},
+			want: []string{
+				"100 = 2 * 2 * 5 * 5",
+			},
+		},
+		{
+			name:    "exact line",
+			limit:   len("38 = 2 * 19\n"),
+			numbers: []int{38},
+			want: []string{
+				"38 = 2 * 19",
+			},
+		},
+		{
+			name:    "long line",
+			limit:   7,
+			numbers: []int{1073741824},
+			want: []string{
+				"1073741824 = 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2",
+			},
+		},
+		{
+			name:    "several lines",
+			limit:   3,
+			numbers: []int{-17, 25, 0},
+			want: []string{
+				"-17 = -1 * 17",
+				"25 = 5 * 5",
+				"0 = 0",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			fact := newFactorizer(t, 1, 1)
+
+			writer := newShortWriter(tt.limit)
+			err := fact.Factorize(context.Background(), tt.numbers, writer)
+			require.NoError(t, err)
+
+			facts := getFact(writer)
+			slices.Sort(tt.want)
+			slices.Sort(facts)
+
+			require.Equal(t, tt.want, facts)
+		})
+	}
+}
+
+func TestShortWriteNoProgress(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 10, 10)
+
+	err := fact.Factorize(context.Background(), generateNumbers(1000), newShortWriter(0))
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, io.ErrShortWrite)
+
+	var shortWrite *ShortWriteError
+	require.ErrorAs(t, err, &shortWrite)
+	require.Zero(t, shortWrite.Written)
+	require.Positive(t, shortWrite.Len)
+}
+
+func TestShortWriteOneByteAtATime(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := append(generateNumbers(1000), -1, -17, math.MaxInt32, math.MinInt)
+
+	writer := newShortWriter(1)
+	err := newFactorizer(t, 10, 1).Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	allNums := make([]int, 0, len(numbers))
+	for _, line := range getFact(writer) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res), "truncated line %q", line)
+		allNums = append(allNums, num)
+	}
+
+	slices.Sort(allNums)
+	slices.Sort(numbers)
+	require.Equal(t, numbers, allNums)
+
+	// the write-full loop of one line is atomic with respect to other write workers
+	concurrent := newShortWriter(1)
+	err = newFactorizer(t, 10, 100).Factorize(context.Background(), numbers, concurrent)
+	require.NoError(t, err)
+
+	concurrentNums := make([]int, 0, len(numbers))
+	for _, line := range getFact(concurrent) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res), "interleaved line %q", line)
+		concurrentNums = append(concurrentNums, num)
+	}
+
+	slices.Sort(concurrentNums)
+	require.Equal(t, numbers, concurrentNums)
+}
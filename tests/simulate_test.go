@@ -0,0 +1,137 @@
+//go:build simulate_test
+
+package fact
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateBottleneck(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cfg      SimConfig
+		workload Profile
+		want     time.Duration
+	}{
+		{
+			name:     "write bound",
+			cfg:      SimConfig{FactWorkers: 4, WriteWorkers: 2},
+			workload: Profile{Count: 100, FactorizationCost: time.Millisecond, WriteCost: 100 * time.Millisecond},
+			want:     5 * time.Second,
+		},
+		{
+			name:     "factorization bound",
+			cfg:      SimConfig{FactWorkers: 2, WriteWorkers: 100},
+			workload: Profile{Count: 1000, FactorizationCost: 10 * time.Millisecond, WriteCost: time.Millisecond},
+			want:     5 * time.Second,
+		},
+		{
+			name:     "single worker each",
+			cfg:      SimConfig{FactWorkers: 1, WriteWorkers: 1},
+			workload: Profile{Count: 10, FactorizationCost: time.Second, WriteCost: time.Second},
+			want:     11 * time.Second,
+		},
+		{
+			name:     "empty",
+			cfg:      SimConfig{FactWorkers: 1, WriteWorkers: 1},
+			workload: Profile{Count: 0, FactorizationCost: time.Second, WriteCost: time.Second},
+			want:     0,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Simulate(tt.cfg, tt.workload)
+			require.InDelta(t, tt.want, report.Duration, float64(tt.want)*0.05)
+		})
+	}
+}
+
+func TestSimulateScaling(t *testing.T) {
+	workload := Profile{Count: 100, FactorizationCost: time.Microsecond, WriteCost: 100 * time.Millisecond}
+
+	first := Simulate(SimConfig{FactWorkers: 16, WriteWorkers: 16}, workload)
+	second := Simulate(SimConfig{FactWorkers: 8, WriteWorkers: 4}, workload)
+
+	require.GreaterOrEqual(t, float64(second.Duration)/float64(first.Duration), 3.5)
+	require.Greater(t, first.Throughput, second.Throughput)
+}
+
+func TestSimulateReport(t *testing.T) {
+	cfg := SimConfig{FactWorkers: 50, WriteWorkers: 1000}
+	workload := Profile{Count: 1000, FactorizationCost: time.Millisecond, WriteCost: time.Second}
+
+	report := Simulate(cfg, workload)
+
+	require.Equal(t, cfg.FactWorkers+cfg.WriteWorkers, report.Goroutines)
+	require.GreaterOrEqual(t, report.MeanLatency, workload.FactorizationCost+workload.WriteCost)
+	require.InDelta(t, float64(workload.Count)/report.Duration.Seconds(), report.Throughput, 1)
+}
+
+func TestSimulateIsCheap(t *testing.T) {
+	start := time.Now()
+
+	report := Simulate(
+		SimConfig{FactWorkers: 100, WriteWorkers: 100_000},
+		Profile{Count: 1_000_000_000, FactorizationCost: time.Millisecond, WriteCost: 100 * time.Millisecond},
+	)
+
+	require.Positive(t, report.Duration)
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+// TestSimulateMemory pins the memory model: SimGoroutineBytes per goroutine plus
+// LineBytes per result that is formatted but not yet written.
+func TestSimulateMemory(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cfg      SimConfig
+		workload Profile
+		inFlight int
+	}{
+		{
+			// the writers hold min(WriteWorkers, FactWorkers) results, and every
+			// factorization worker blocks holding one more
+			name:     "write bound",
+			cfg:      SimConfig{FactWorkers: 4, WriteWorkers: 2},
+			workload: Profile{Count: 100, FactorizationCost: time.Millisecond, WriteCost: 100 * time.Millisecond, LineBytes: 100},
+			inFlight: 6,
+		},
+		{
+			// the writers hold min(WriteWorkers, FactWorkers) results, and no
+			// factorization worker blocks
+			name:     "factorization bound",
+			cfg:      SimConfig{FactWorkers: 2, WriteWorkers: 100},
+			workload: Profile{Count: 1000, FactorizationCost: 10 * time.Millisecond, WriteCost: time.Millisecond, LineBytes: 100},
+			inFlight: 2,
+		},
+		{
+			name:     "empty",
+			cfg:      SimConfig{FactWorkers: 3, WriteWorkers: 3},
+			workload: Profile{Count: 0, FactorizationCost: time.Second, WriteCost: time.Second, LineBytes: 100},
+			inFlight: 0,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Simulate(tt.cfg, tt.workload)
+
+			stacks := (tt.cfg.FactWorkers + tt.cfg.WriteWorkers) * SimGoroutineBytes
+			require.Equal(t, stacks+tt.inFlight*tt.workload.LineBytes, report.PeakMemory)
+		})
+	}
+}
+
+func TestSimulateMemoryWriteWorkers(t *testing.T) {
+	workload := Profile{Count: 1_000_000, FactorizationCost: time.Millisecond, WriteCost: 100 * time.Millisecond, LineBytes: 64}
+
+	small := Simulate(SimConfig{FactWorkers: 16, WriteWorkers: 1000}, workload)
+	large := Simulate(SimConfig{FactWorkers: 16, WriteWorkers: 100_000}, workload)
+
+	// the answer to "1000 or 100000 write workers": the extra stacks dominate
+	require.GreaterOrEqual(t, large.PeakMemory-small.PeakMemory, 99_000*SimGoroutineBytes)
+}
@@ -0,0 +1,121 @@
+//go:build smallestfirst_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newSmallestFirstFactorizer(t *testing.T, factWorkers, writeWorkers int) *factorizerImpl {
+	t.Helper()
+
+	fact, err := New(
+		WithFactorizationWorkers(factWorkers),
+		WithWriteWorkers(writeWorkers),
+		WithSmallestFirstScheduling(),
+	)
+	require.NoError(t, err)
+
+	return fact
+}
+
+func TestSmallestFirstCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := generateNumbers(100_000)
+	slices.Reverse(numbers)
+
+	writer := newWriter()
+	err := newSmallestFirstFactorizer(t, 10, 10).Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	allNums := make([]int, 0, len(numbers))
+	for _, line := range getFact(writer) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		allNums = append(allNums, num)
+	}
+
+	slices.Sort(allNums)
+	slices.Sort(numbers)
+	require.Equal(t, numbers, allNums)
+}
+
+func TestSmallestFirstOrder(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const count = 200
+
+	// magnitudes are unique and arrive largest first; odd ones are negative
+	numbers := make([]int, 0, count)
+	for i := count - 1; i >= 0; i-- {
+		n := i
+		if n%2 == 1 {
+			n = -n
+		}
+
+		numbers = append(numbers, n)
+	}
+
+	writer := newSleepWriter(time.Millisecond)
+
+	err := newSmallestFirstFactorizer(t, 1, 1).Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	require.Len(t, lines, len(numbers))
+
+	// only numbers already taken by the workers may overtake cheaper ones
+	const slack = 10
+
+	for i, line := range lines {
+		num, _ := parseLine(t, line)
+		require.LessOrEqual(t, max(num, -num), i+slack, "number %d written at position %d", num, i)
+	}
+}
+
+func TestSmallestFirstUnderDeadline(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	// the expensive number comes first and would occupy the only worker until the deadline
+	const expensive = 1_000_003
+
+	numbers := append([]int{expensive}, generateNumbers(1000)...)
+
+	fact, err := New(
+		WithFactorizationWorkers(1),
+		WithWriteWorkers(1),
+		WithSmallestFirstScheduling(),
+		WithFaultInjector(stallOn(expensive)),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*500)
+	t.Cleanup(cancel)
+
+	writer := newWriter()
+
+	err = fact.Factorize(ctx, numbers, writer)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	lines := getFact(writer)
+	require.Len(t, lines, 1000)
+
+	const slack = 10
+
+	allNums := make([]int, 0, len(lines))
+	for i, line := range lines {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		require.LessOrEqual(t, num, i+slack, "number %d written at position %d", num, i)
+		allNums = append(allNums, num)
+	}
+
+	slices.Sort(allNums)
+	require.Equal(t, generateNumbers(1000), allNums)
+}
@@ -0,0 +1,124 @@
+//go:build smoothness_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newSmoothFactorizer(t *testing.T, bound int) *factorizerImpl {
+	t.Helper()
+
+	fact, err := New(
+		WithFactorizationWorkers(4),
+		WithWriteWorkers(4),
+		WithSmoothnessBound(bound),
+	)
+	require.NoError(t, err)
+
+	return fact
+}
+
+func isSmooth(n, bound int) bool {
+	n = max(n, -n)
+
+	for p := 2; p <= bound && n > 1; p++ {
+		for n%p == 0 {
+			n /= p
+		}
+	}
+
+	return n <= 1
+}
+
+func TestSmoothnessInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	for _, bound := range []int{1, 0, -5} {
+		_, err := New(WithSmoothnessBound(bound))
+
+		require.ErrorContains(t, err, "smoothness")
+		require.ErrorContains(t, err, strconv.Itoa(bound))
+	}
+}
+
+func TestSmoothnessGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := []int{100, -17, 1024, 97, 101, 202, 1, -1}
+	want := []string{
+		"100 = 2 * 2 * 5 * 5",
+		"-17 = -1 * 17",
+		"1024 = 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2 * 2",
+		"97 = 97",
+		"101: not 100-smooth",
+		"202: not 100-smooth",
+		"1 = 1",
+		"-1 = -1",
+	}
+
+	writer := newWriter()
+	err := newSmoothFactorizer(t, 100).Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	slices.Sort(lines)
+	slices.Sort(want)
+
+	require.Equal(t, want, lines)
+}
+
+func TestSmoothnessCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const bound = 50
+
+	numbers := generateNumbers(20_000)[1:]
+
+	writer := newWriter()
+	err := newSmoothFactorizer(t, bound).Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	require.Len(t, lines, len(numbers))
+
+	for _, line := range lines {
+		if left, found := strings.CutSuffix(line, ": not 50-smooth"); found {
+			n := strToInt(t, left)
+			require.False(t, isSmooth(n, bound), "%d is %d-smooth", n, bound)
+
+			continue
+		}
+
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		require.True(t, isSmooth(num, bound), "%d is not %d-smooth", num, bound)
+	}
+}
+
+func TestSmoothnessSkipsHardNumbers(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	// trial division by the primes up to the bound rejects these, the cofactor is never factorized
+	numbers := slices.Repeat(append(slices.Clone(hardSemiprimes), 9223372036854775783), 25)
+
+	start := time.Now()
+
+	writer := newWriter()
+	err := newSmoothFactorizer(t, 1000).Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	require.Less(t, time.Since(start), time.Second)
+
+	for _, line := range getFact(writer) {
+		require.True(t, strings.HasSuffix(line, ": not 1000-smooth"), "line %q", line)
+	}
+}
@@ -0,0 +1,126 @@
+//go:build sortedoutput_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newSortedFactorizer(t *testing.T, factWorkers, writeWorkers int) *factorizerImpl {
+	t.Helper()
+
+	fact, err := New(
+		WithFactorizationWorkers(factWorkers),
+		WithWriteWorkers(writeWorkers),
+		WithSortedByValueOutput(),
+	)
+	require.NoError(t, err)
+
+	return fact
+}
+
+func requireSortedLines(t *testing.T, lines []string) []int {
+	t.Helper()
+
+	nums := make([]int, 0, len(lines))
+	for _, line := range lines {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+
+		nums = append(nums, num)
+	}
+
+	require.True(t, slices.IsSorted(nums), "output is not sorted by value")
+
+	return nums
+}
+
+func TestSortedOutputGolden(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name    string
+		numbers []int
+		want    string
+	}{
+		{
+			name:    "readme example",
+			numbers: []int{100, -17, 25, 38},
+			want:    "-17 = -1 * 17\n25 = 5 * 5\n38 = 2 * 19\n100 = 2 * 2 * 5 * 5\n",
+		},
+		{
+			name:    "duplicates and edges",
+			numbers: []int{1, 4, math.MinInt, 0, 4, -1},
+			want:    minIntLine() + "\n-1 = -1\n0 = 0\n1 = 1\n4 = 2 * 2\n4 = 2 * 2\n",
+		},
+		{
+			name:    "empty",
+			numbers: []int{},
+			want:    "",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := newWriter()
+
+			err := newSortedFactorizer(t, 4, 4).Factorize(context.Background(), tt.numbers, writer)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.want, writer.String())
+		})
+	}
+}
+
+func TestSortedOutputCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := generateNumbers(10_000)
+	rand.Shuffle(len(numbers), func(i, j int) {
+		numbers[i], numbers[j] = numbers[j], numbers[i]
+	})
+
+	writer := newWriter()
+
+	// several write workers must not interleave the sorted output
+	err := newSortedFactorizer(t, 10, 10).Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	require.Equal(t, generateNumbers(10_000), requireSortedLines(t, getFact(writer)))
+}
+
+func TestSortedOutputCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	t.Cleanup(cancel)
+
+	numbers := generateNumbers(1000)
+	slices.Reverse(numbers)
+
+	writer := newSleepWriter(time.Millisecond * 10)
+
+	err := newSortedFactorizer(t, 2, 2).Factorize(ctx, numbers, writer)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	// whatever was written before cancellation is still in order
+	requireSortedLines(t, getFact(writer))
+}
+
+func TestSortedOutputWriterError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	errWrite := errors.New("disk full")
+
+	err := newSortedFactorizer(t, 2, 2).Factorize(context.Background(), generateNumbers(100), newSleepErrorWriter(0, errWrite))
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errWrite)
+}
@@ -0,0 +1,156 @@
+//go:build source_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parseTaggedLine(t *testing.T, line string) (string, int, []int) {
+	t.Helper()
+
+	tag, rest, found := strings.Cut(line, ": ")
+	require.True(t, found, "line %q has no source tag", line)
+
+	num, res := parseLine(t, rest)
+
+	return tag, num, res
+}
+
+func TestSourceGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name    string
+		sources []Source
+		want    []string
+	}{
+		{
+			name: "two sources",
+			sources: []Source{
+				{Tag: "a.txt", Numbers: []int{100, -17}},
+				{Tag: "b.txt", Numbers: []int{25, 38}},
+			},
+			want: []string{
+				"a.txt: 100 = 2 * 2 * 5 * 5",
+				"a.txt: -17 = -1 * 17",
+				"b.txt: 25 = 5 * 5",
+				"b.txt: 38 = 2 * 19",
+			},
+		},
+		{
+			name: "same number in different sources",
+			sources: []Source{
+				{Tag: "stdin", Numbers: []int{4}},
+				{Tag: "tcp://10.0.0.1:9000", Numbers: []int{4}},
+			},
+			want: []string{
+				"stdin: 4 = 2 * 2",
+				"tcp://10.0.0.1:9000: 4 = 2 * 2",
+			},
+		},
+		{
+			name: "untagged source",
+			sources: []Source{
+				{Numbers: []int{0, 1}},
+				{Tag: "x", Numbers: []int{2}},
+			},
+			want: []string{
+				"0 = 0",
+				"1 = 1",
+				"x: 2 = 2",
+			},
+		},
+		{
+			name: "empty source",
+			sources: []Source{
+				{Tag: "empty", Numbers: []int{}},
+				{Tag: "one", Numbers: []int{1}},
+			},
+			want: []string{
+				"one: 1 = 1",
+			},
+		},
+		{
+			name:    "no sources",
+			sources: []Source{},
+			want:    []string{},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			fact := newFactorizer(t, 2, 2)
+
+			writer := newWriter()
+			err := fact.FactorizeSources(context.Background(), tt.sources, writer)
+			require.NoError(t, err)
+
+			facts := getFact(writer)
+			slices.Sort(tt.want)
+			slices.Sort(facts)
+
+			require.Equal(t, tt.want, facts)
+		})
+	}
+}
+
+func TestSourceAttribution(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const perSource = 1000
+
+	sources := make([]Source, 0, 10)
+	for i := range 10 {
+		numbers := make([]int, 0, perSource)
+		for j := range perSource {
+			numbers = append(numbers, i*perSource+j)
+		}
+
+		sources = append(sources, Source{Tag: "src" + strconv.Itoa(i), Numbers: numbers})
+	}
+
+	fact := newFactorizer(t, 100, 100)
+
+	writer := newWriter()
+	err := fact.FactorizeSources(context.Background(), sources, writer)
+	require.NoError(t, err)
+
+	got := make(map[string][]int, len(sources))
+	for _, line := range getFact(writer) {
+		tag, num, res := parseTaggedLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		got[tag] = append(got[tag], num)
+	}
+
+	require.Len(t, got, len(sources))
+	for _, src := range sources {
+		nums := got[src.Tag]
+		slices.Sort(nums)
+		require.Equal(t, src.Numbers, nums, "source %s", src.Tag)
+	}
+}
+
+func TestSourceCancelWriterNoOp(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fact := newFactorizer(t, 10, 10)
+
+	writer := newWriter()
+	err := fact.FactorizeSources(ctx, []Source{
+		{Tag: "a", Numbers: generateNumbers(1000)},
+		{Tag: "b", Numbers: generateNumbers(1000)},
+	}, writer)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	require.Zero(t, len(writer.String()))
+}
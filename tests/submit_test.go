@@ -0,0 +1,157 @@
+//go:build submit_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func waitJob(t *testing.T, job *Job, timeout time.Duration) {
+	t.Helper()
+
+	select {
+	case <-job.Done():
+	case <-time.After(timeout):
+		require.FailNow(t, "job did not finish in time")
+	}
+}
+
+func TestSubmitDoesNotBlock(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 2, 2)
+
+	numbers := generateNumbers(20)
+	writer := newSleepWriter(time.Millisecond * 50)
+
+	start := time.Now()
+
+	job, err := fact.Submit(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	require.Less(t, time.Since(start), time.Millisecond*50)
+	require.NoError(t, job.Err(), "Err must be nil until the job is done")
+
+	waitJob(t, job, time.Second*5)
+	require.NoError(t, job.Err())
+
+	allNums := make([]int, 0, len(numbers))
+	for _, line := range getFact(writer) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		allNums = append(allNums, num)
+	}
+
+	slices.Sort(allNums)
+	require.Equal(t, numbers, allNums)
+}
+
+func TestSubmitSeveralJobs(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 4, 4)
+
+	jobs := make([]*Job, 0, 10)
+	writers := make([]*concurrentWriter, 0, 10)
+
+	for i := range 10 {
+		writer := newWriter()
+
+		job, err := fact.Submit(context.Background(), generateNumbers(1000*(i+1)), writer)
+		require.NoError(t, err)
+
+		jobs = append(jobs, job)
+		writers = append(writers, writer)
+	}
+
+	for i, job := range jobs {
+		waitJob(t, job, time.Second*10)
+		require.NoError(t, job.Err())
+		require.Len(t, getFact(writers[i]), 1000*(i+1))
+	}
+}
+
+func TestSubmitCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 2, 2)
+
+	job, err := fact.Submit(context.Background(), generateNumbers(1_000_000), newSleepWriter(time.Millisecond))
+	require.NoError(t, err)
+
+	time.Sleep(time.Millisecond * 50)
+
+	job.Cancel()
+	waitJob(t, job, time.Second)
+	require.ErrorIs(t, job.Err(), ErrFactorizationCancelled)
+
+	job.Cancel()
+	require.ErrorIs(t, job.Err(), ErrFactorizationCancelled, "Cancel must be idempotent")
+}
+
+func TestSubmitCancelAfterDone(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 2, 2)
+
+	job, err := fact.Submit(context.Background(), []int{100, -17}, newWriter())
+	require.NoError(t, err)
+
+	waitJob(t, job, time.Second)
+
+	job.Cancel()
+	require.NoError(t, job.Err(), "cancelling a finished job must not change its result")
+}
+
+func TestSubmitContextCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 2, 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	job, err := fact.Submit(ctx, generateNumbers(1_000_000), newSleepWriter(time.Millisecond))
+	require.NoError(t, err)
+
+	waitJob(t, job, time.Second)
+	require.ErrorIs(t, job.Err(), ErrFactorizationCancelled)
+}
+
+func TestSubmitCancelledContext(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 2, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	writer := newWriter()
+
+	job, err := fact.Submit(ctx, generateNumbers(1000), writer)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+	require.Nil(t, job)
+
+	require.Zero(t, len(writer.String()))
+}
+
+func TestSubmitWriterError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 2, 2)
+
+	errWrite := errors.New("connection reset")
+
+	job, err := fact.Submit(context.Background(), generateNumbers(1000), newSleepErrorWriter(time.Millisecond, errWrite))
+	require.NoError(t, err)
+
+	waitJob(t, job, time.Second)
+	require.ErrorIs(t, job.Err(), ErrWriterInteraction)
+	require.ErrorIs(t, job.Err(), errWrite)
+}
@@ -0,0 +1,158 @@
+//go:build synchronous_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newSynchronousFactorizer(t *testing.T) *factorizerImpl {
+	t.Helper()
+
+	fact, err := New(WithSynchronousMode())
+	require.NoError(t, err)
+
+	return fact
+}
+
+func TestSynchronousGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name    string
+		numbers []int
+		want    string
+	}{
+		{
+			name:    "readme example",
+			numbers: []int{100, -17, 25, 38},
+			want:    "100 = 2 * 2 * 5 * 5\n-17 = -1 * 17\n25 = 5 * 5\n38 = 2 * 19\n",
+		},
+		{
+			name:    "edge values",
+			numbers: []int{0, 1, -1, math.MinInt},
+			want:    "0 = 0\n1 = 1\n-1 = -1\n" + minIntLine() + "\n",
+		},
+		{
+			name:    "empty",
+			numbers: []int{},
+			want:    "",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := newWriter()
+
+			err := newSynchronousFactorizer(t).Factorize(context.Background(), tt.numbers, writer)
+			require.NoError(t, err)
+
+			// without workers the output follows the input order exactly
+			require.Equal(t, tt.want, writer.String())
+		})
+	}
+}
+
+func TestSynchronousMatchesConcurrent(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := generateNumbers(10_000)
+
+	syncWriter := newWriter()
+	require.NoError(t, newSynchronousFactorizer(t).Factorize(context.Background(), numbers, syncWriter))
+
+	concurrent := newWriter()
+	require.NoError(t, newFactorizer(t, 10, 10).Factorize(context.Background(), numbers, concurrent))
+
+	syncLines := getFact(syncWriter)
+	concurrentLines := getFact(concurrent)
+
+	require.ElementsMatch(t, concurrentLines, syncLines)
+
+	for i, line := range syncLines {
+		num, res := parseLine(t, line)
+		require.Equal(t, numbers[i], num)
+		require.True(t, checkFactorization(num, res))
+	}
+}
+
+func TestSynchronousNoGoroutines(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newSynchronousFactorizer(t)
+
+	idle := inspectNumGoroutines(t, func() {
+		time.Sleep(time.Millisecond * 20)
+	})
+
+	count := inspectNumGoroutines(t, func() {
+		err := fact.Factorize(context.Background(), generateNumbers(20), newSleepWriter(time.Millisecond))
+		require.NoError(t, err)
+	})
+
+	// the pipeline runs on the calling goroutine and starts nothing else
+	require.LessOrEqual(t, count, idle)
+}
+
+func TestSynchronousOneWritePerLine(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	writer := newRecordingWriter()
+	require.NoError(t, newSynchronousFactorizer(t).Factorize(context.Background(), []int{4, 6}, writer))
+
+	require.Equal(t, []string{"4 = 2 * 2\n", "6 = 2 * 3\n"}, writer.Calls())
+}
+
+func TestSynchronousCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	t.Run("before start", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		writer := newWriter()
+
+		err := newSynchronousFactorizer(t).Factorize(ctx, generateNumbers(100), writer)
+		require.ErrorIs(t, err, ErrFactorizationCancelled)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Empty(t, writer.String())
+	})
+
+	t.Run("during run", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+		t.Cleanup(cancel)
+
+		writer := newSleepWriter(time.Millisecond * 10)
+		start := time.Now()
+
+		err := newSynchronousFactorizer(t).Factorize(ctx, generateNumbers(1000), writer)
+		require.ErrorIs(t, err, ErrFactorizationCancelled)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		require.Less(t, time.Since(start), time.Millisecond*200)
+
+		// the prefix written before the deadline is still in input order
+		for i, line := range getFact(writer) {
+			num, _ := parseLine(t, line)
+			require.Equal(t, i, num)
+		}
+	})
+}
+
+func TestSynchronousWriterError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	errWrite := errors.New("disk full")
+	writer := newSleepErrorWriter(0, errWrite)
+
+	err := newSynchronousFactorizer(t).Factorize(context.Background(), generateNumbers(100), writer)
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errWrite)
+	require.NotErrorIs(t, err, ErrFactorizationCancelled)
+}
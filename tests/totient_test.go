@@ -0,0 +1,122 @@
+//go:build totient_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func bruteTotient(n int) int {
+	n = max(n, -n)
+
+	phi := 0
+	for k := 1; k <= n; k++ {
+		if bruteGCD(k, n) == 1 {
+			phi++
+		}
+	}
+
+	return phi
+}
+
+func parseTotientLine(t *testing.T, line string) (int, int) {
+	t.Helper()
+
+	left, right, found := strings.Cut(line, ": ")
+	require.True(t, found, "malformed totient line %q", line)
+
+	return strToInt(t, left), strToInt(t, right)
+}
+
+func TestTotientGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := []int{1, 2, 9, 36, 97, -12, 1_000_000}
+	want := []string{
+		"1: 1",
+		"2: 1",
+		"9: 6",
+		"36: 12",
+		"97: 96",
+		"-12: 4",
+		"1000000: 400000",
+	}
+
+	writer := newWriter()
+	err := newFactorizer(t, 3, 3).Totient(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	slices.Sort(lines)
+	slices.Sort(want)
+
+	require.Equal(t, want, lines)
+}
+
+func TestTotientCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := generateNumbers(3000)[1:]
+
+	writer := newWriter()
+	err := newFactorizer(t, 8, 8).Totient(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	lines := getFact(writer)
+	require.Len(t, lines, len(numbers))
+
+	for _, line := range lines {
+		n, phi := parseTotientLine(t, line)
+		require.Equal(t, bruteTotient(n), phi, "line %q", line)
+	}
+}
+
+func TestTotientValues(t *testing.T) {
+	skipIfNot64Bit(t)
+	deferrableLeakDetection(t)
+
+	numbers := []int{36, 1, math.MaxInt, 97, -12}
+
+	got, err := newFactorizer(t, 4, 1).TotientValues(context.Background(), numbers)
+	require.NoError(t, err)
+
+	// results follow input order, not completion order
+	require.Equal(t, []int{12, 1, 7 * 6 * 72 * 126 * 336 * 92736 * 649656, 96, 4}, got)
+}
+
+func TestTotientErrors(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	t.Run("writer", func(t *testing.T) {
+		errWrite := errors.New("sink closed")
+
+		err := newFactorizer(t, 2, 2).Totient(context.Background(), generateNumbers(1000)[1:], newSleepErrorWriter(time.Millisecond, errWrite))
+		require.ErrorIs(t, err, ErrWriterInteraction)
+		require.ErrorIs(t, err, errWrite)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+		t.Cleanup(cancel)
+
+		err := newFactorizer(t, 2, 2).Totient(ctx, generateNumbers(1_000_000)[1:], newSleepWriter(time.Millisecond))
+		require.ErrorIs(t, err, ErrFactorizationCancelled)
+	})
+
+	t.Run("cancel values", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		got, err := newFactorizer(t, 2, 2).TotientValues(ctx, []int{12})
+		require.ErrorIs(t, err, ErrFactorizationCancelled)
+		require.Nil(t, got)
+	})
+}
@@ -0,0 +1,155 @@
+//go:build tracing_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type hookCtxKey struct{}
+
+// recordingHook is a CallHook that records what the pipeline reports. The otel
+// adapter built on the same interface lives in the facttrace subpackage: tracing
+// is enabled with WithCallHook(hook), where hook comes from facttrace.New(provider),
+// rather than with a WithTracerProvider option, so that fact never imports otel.
+type recordingHook struct {
+	mx         sync.Mutex
+	started    []int
+	factorized []int
+	finished   []error
+	foreignCtx int
+}
+
+func (h *recordingHook) CallStarted(ctx context.Context, count int) context.Context {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	h.started = append(h.started, count)
+
+	return context.WithValue(ctx, hookCtxKey{}, "call")
+}
+
+func (h *recordingHook) NumberFactorized(ctx context.Context, n int, elapsed time.Duration) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	if ctx.Value(hookCtxKey{}) != "call" || elapsed < 0 {
+		h.foreignCtx++
+	}
+
+	h.factorized = append(h.factorized, n)
+}
+
+func (h *recordingHook) CallFinished(ctx context.Context, err error) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	if ctx.Value(hookCtxKey{}) != "call" {
+		h.foreignCtx++
+	}
+
+	h.finished = append(h.finished, err)
+}
+
+func (h *recordingHook) snapshot() (started, factorized []int, finished []error, foreignCtx int) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+
+	return slices.Clone(h.started), slices.Clone(h.factorized), slices.Clone(h.finished), h.foreignCtx
+}
+
+func TestCallHookInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithCallHook(nil))
+
+	require.ErrorContains(t, err, "call hook")
+}
+
+func TestCallHookLifecycle(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name    string
+		numbers []int
+	}{
+		{name: "readme example", numbers: []int{100, -17, 25, 38}},
+		{name: "repeated inputs", numbers: []int{4, 4}},
+		{name: "empty", numbers: []int{}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := new(recordingHook)
+
+			fact, err := New(
+				WithFactorizationWorkers(2),
+				WithWriteWorkers(2),
+				WithCallHook(hook),
+			)
+			require.NoError(t, err)
+
+			err = fact.Factorize(context.Background(), tt.numbers, newWriter())
+			require.NoError(t, err)
+
+			started, factorized, finished, foreignCtx := hook.snapshot()
+
+			require.Equal(t, []int{len(tt.numbers)}, started)
+			require.ElementsMatch(t, tt.numbers, factorized)
+			require.Equal(t, []error{nil}, finished)
+
+			// the context returned by CallStarted is handed to the other hooks
+			require.Zero(t, foreignCtx)
+		})
+	}
+}
+
+func TestCallHookCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	hook := new(recordingHook)
+
+	fact, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithCallHook(hook),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	err = fact.Factorize(ctx, generateNumbers(1_000_000), newSleepWriter(time.Millisecond))
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	// the call must already be finished when Factorize returns
+	_, _, finished, _ := hook.snapshot()
+	require.Len(t, finished, 1)
+	require.ErrorIs(t, finished[0], ErrFactorizationCancelled)
+}
+
+func TestCallHookWriterError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	hook := new(recordingHook)
+
+	fact, err := New(WithCallHook(hook))
+	require.NoError(t, err)
+
+	errWrite := errors.New("disk full")
+
+	err = fact.Factorize(context.Background(), []int{4}, newSleepErrorWriter(0, errWrite))
+	require.ErrorIs(t, err, errWrite)
+
+	_, _, finished, _ := hook.snapshot()
+	require.Len(t, finished, 1)
+	require.ErrorIs(t, finished[0], ErrWriterInteraction)
+	require.ErrorIs(t, finished[0], errWrite)
+}
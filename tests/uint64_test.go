@@ -0,0 +1,162 @@
+//go:build uint64_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"math/bits"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func checkFactorizationUint64(t *testing.T, line string) uint64 {
+	t.Helper()
+
+	left, right, ok := strings.Cut(line, " = ")
+	require.True(t, ok, "malformed line %q", line)
+
+	n, err := strconv.ParseUint(left, 10, 64)
+	require.NoError(t, err)
+
+	product := big.NewInt(1)
+	prev := uint64(0)
+
+	for _, part := range strings.Split(right, " * ") {
+		f, err := strconv.ParseUint(part, 10, 64)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, f, prev, "factors of %d are not sorted", n)
+
+		if n > 1 {
+			require.True(t, new(big.Int).SetUint64(f).ProbablyPrime(20), "%d is not prime in %q", f, line)
+		}
+
+		product.Mul(product, new(big.Int).SetUint64(f))
+		prev = f
+	}
+
+	require.Equal(t, new(big.Int).SetUint64(n).String(), product.String(), "product mismatch in %q", line)
+
+	return n
+}
+
+func TestFactorizeUint64GoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name    string
+		numbers []uint64
+		want    []string
+	}{
+		{
+			name:    "small",
+			numbers: []uint64{0, 1, 100, 25, 38},
+			want: []string{
+				"0 = 0",
+				"1 = 1",
+				"100 = 2 * 2 * 5 * 5",
+				"25 = 5 * 5",
+				"38 = 2 * 19",
+			},
+		},
+		{
+			name:    "max uint64",
+			numbers: []uint64{math.MaxUint64},
+			want: []string{
+				"18446744073709551615 = 3 * 5 * 17 * 257 * 641 * 65537 * 6700417",
+			},
+		},
+		{
+			name:    "largest prime",
+			numbers: []uint64{math.MaxUint64 - 58},
+			want: []string{
+				"18446744073709551557 = 18446744073709551557",
+			},
+		},
+		{
+			name:    "above max int64",
+			numbers: []uint64{1 << 63, math.MaxUint64 - 1},
+			want: []string{
+				"9223372036854775808 = " + strings.Repeat("2 * ", 62) + "2",
+				"18446744073709551614 = 2 * 7 * 7 * 73 * 127 * 337 * 92737 * 649657",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			fact := newFactorizer(t, 2, 2)
+
+			writer := newWriter()
+			err := fact.FactorizeUint64(context.Background(), tt.numbers, writer)
+			require.NoError(t, err)
+
+			facts := getFact(writer)
+			slices.Sort(facts)
+			slices.Sort(tt.want)
+
+			require.Equal(t, tt.want, facts)
+		})
+	}
+}
+
+func TestFactorizeUint64Correctness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	// values near 2^64 are k * 2^s with a small odd k, so that trial division stays cheap
+	numbers := make([]uint64, 0, 600)
+	for k := range uint64(300) {
+		odd := 2*k + 1
+		numbers = append(numbers, k, odd<<(64-bits.Len64(odd)))
+	}
+
+	fact := newFactorizer(t, 10, 10)
+
+	writer := newWriter()
+	err := fact.FactorizeUint64(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	allNums := make([]uint64, 0, len(numbers))
+	for _, line := range getFact(writer) {
+		allNums = append(allNums, checkFactorizationUint64(t, line))
+	}
+
+	slices.Sort(allNums)
+	slices.Sort(numbers)
+	require.Equal(t, numbers, allNums)
+}
+
+func TestFactorizeUint64Cancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact := newFactorizer(t, 2, 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	numbers := make([]uint64, 1_000_000)
+	for i := range numbers {
+		numbers[i] = math.MaxUint64 - uint64(i)
+	}
+
+	err := fact.FactorizeUint64(ctx, numbers, newSleepWriter(time.Millisecond))
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+}
+
+func TestFactorizeUint64WriterError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	errWrite := errors.New("disk full")
+	fact := newFactorizer(t, 2, 2)
+
+	err := fact.FactorizeUint64(context.Background(), []uint64{math.MaxUint64, 1 << 63}, newSleepErrorWriter(0, errWrite))
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errWrite)
+}
@@ -0,0 +1,156 @@
+//go:build unitpolicy_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitPolicyInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithUnitPolicy(UnitPolicy(-1)))
+
+	require.ErrorContains(t, err, "unit policy")
+	require.ErrorContains(t, err, "-1")
+}
+
+func TestUnitPolicyGoldenOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := []int{0, 1, -1, 12, -17}
+
+	testCases := []struct {
+		name   string
+		policy UnitPolicy
+		want   []string
+	}{
+		{
+			name:   "keep",
+			policy: UnitKeep,
+			want: []string{
+				"0 = 0",
+				"1 = 1",
+				"-1 = -1",
+				"12 = 2 * 2 * 3",
+				"-17 = -1 * 17",
+			},
+		},
+		{
+			name:   "skip",
+			policy: UnitSkip,
+			want: []string{
+				"-1 = -1",
+				"12 = 2 * 2 * 3",
+				"-17 = -1 * 17",
+			},
+		},
+		{
+			name:   "empty product",
+			policy: UnitEmptyProduct,
+			want: []string{
+				"0 = 0",
+				"1 = ",
+				"-1 = -1",
+				"12 = 2 * 2 * 3",
+				"-17 = -1 * 17",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			fact, err := New(
+				WithFactorizationWorkers(2),
+				WithWriteWorkers(2),
+				WithUnitPolicy(tt.policy),
+			)
+			require.NoError(t, err)
+
+			writer := newWriter()
+			err = fact.Factorize(context.Background(), numbers, writer)
+			require.NoError(t, err)
+
+			facts := getFact(writer)
+			slices.Sort(facts)
+			slices.Sort(tt.want)
+
+			require.Equal(t, tt.want, facts)
+		})
+	}
+}
+
+func TestUnitPolicyDefaultIsKeep(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	writer := newWriter()
+	err := newFactorizer(t, 1, 1).Factorize(context.Background(), []int{0, 1}, writer)
+	require.NoError(t, err)
+
+	facts := getFact(writer)
+	slices.Sort(facts)
+	require.Equal(t, []string{"0 = 0", "1 = 1"}, facts)
+}
+
+func TestUnitPolicyError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name    string
+		numbers []int
+		want    int
+	}{
+		{name: "zero", numbers: []int{12, 0, 13}, want: 0},
+		{name: "one", numbers: []int{12, 1, 13}, want: 1},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			fact, err := New(
+				WithFactorizationWorkers(1),
+				WithWriteWorkers(1),
+				WithUnitPolicy(UnitError),
+			)
+			require.NoError(t, err)
+
+			writer := newWriter()
+			err = fact.Factorize(context.Background(), tt.numbers, writer)
+
+			var unitErr *UnitInputError
+			require.True(t, errors.As(err, &unitErr), "got %v", err)
+			require.Equal(t, tt.want, unitErr.N)
+			require.NotErrorIs(t, err, ErrWriterInteraction)
+			require.NotErrorIs(t, err, ErrFactorizationCancelled)
+
+			for _, line := range getFact(writer) {
+				num, _ := parseLine(t, line)
+				require.NotEqual(t, tt.want, num, "rejected input must not be written")
+			}
+		})
+	}
+}
+
+func TestUnitPolicyErrorMinusOne(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(1),
+		WithWriteWorkers(1),
+		WithUnitPolicy(UnitError),
+	)
+	require.NoError(t, err)
+
+	// -1 = -1 is a valid factorization and is not rejected
+	writer := newWriter()
+	err = fact.Factorize(context.Background(), []int{-1, 2}, writer)
+	require.NoError(t, err)
+
+	facts := getFact(writer)
+	slices.Sort(facts)
+	require.Equal(t, []string{"-1 = -1", "2 = 2"}, facts)
+}
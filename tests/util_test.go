@@ -72,7 +72,7 @@ func ctxWithCancel(t *testing.T) (context.Context, context.CancelFunc) {
 }
 
 func newFactorizer(
-	t *testing.T,
+	t testing.TB,
 	factWorkers int,
 	writeWorkers int,
 ) *factorizerImpl {
@@ -109,6 +109,130 @@ func runCancel(t *testing.T, tt *testCancel) {
 	tt.err(t, err)
 }
 
+// setGOMAXPROCS emulates a CPU-limited container for the duration of the test.
+func setGOMAXPROCS(t *testing.T, procs int) {
+	t.Helper()
+
+	prev := runtime.GOMAXPROCS(procs)
+	t.Cleanup(func() {
+		runtime.GOMAXPROCS(prev)
+	})
+}
+
+// countWorkers keeps every worker busy with enough slow writes to observe both pools.
+func countWorkers(t *testing.T, fact *factorizerImpl, want int) int {
+	t.Helper()
+
+	var err error
+
+	got := inspectNumGoroutines(t, func() {
+		err = fact.Factorize(context.Background(), generateNumbers(2*want), newSleepWriter(time.Millisecond*200))
+	})
+
+	require.NoError(t, err)
+
+	return got
+}
+
+// scriptedFaults is a FaultInjector whose hooks are optional closures.
+type scriptedFaults struct {
+	dispatch  func(ctx context.Context, n int)
+	factorize func(ctx context.Context, worker, n int)
+	write     func(ctx context.Context, k int) error
+}
+
+func (s *scriptedFaults) BeforeDispatch(ctx context.Context, n int) {
+	if s.dispatch != nil {
+		s.dispatch(ctx, n)
+	}
+}
+
+func (s *scriptedFaults) BeforeFactorize(ctx context.Context, worker, n int) {
+	if s.factorize != nil {
+		s.factorize(ctx, worker, n)
+	}
+}
+
+func (s *scriptedFaults) BeforeWrite(ctx context.Context, k int) error {
+	if s.write != nil {
+		return s.write(ctx, k)
+	}
+
+	return nil
+}
+
+// stallOn blocks the factorization of the given numbers until ctx is done,
+// independently of how fast the factorization algorithm is.
+func stallOn(numbers ...int) *scriptedFaults {
+	return &scriptedFaults{
+		factorize: func(ctx context.Context, _, n int) {
+			if slices.Contains(numbers, n) {
+				<-ctx.Done()
+			}
+		},
+	}
+}
+
+// mapCache is a FactorCache that counts its calls.
+type mapCache struct {
+	mx   sync.Mutex
+	m    map[int][]int
+	gets int
+	hits int
+	puts int
+}
+
+func newMapCache() *mapCache {
+	return &mapCache{m: make(map[int][]int)}
+}
+
+func (c *mapCache) Get(n int) ([]int, bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.gets++
+
+	f, ok := c.m[n]
+	if ok {
+		c.hits++
+	}
+
+	return slices.Clone(f), ok
+}
+
+func (c *mapCache) Put(n int, f []int) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.puts++
+	c.m[n] = slices.Clone(f)
+}
+
+// peek reports whether n is cached without counting a get.
+func (c *mapCache) peek(n int) ([]int, bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	f, ok := c.m[n]
+
+	return slices.Clone(f), ok
+}
+
+// evict drops every entry, so that the next lookups miss again.
+func (c *mapCache) evict() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	clear(c.m)
+}
+
+func (c *mapCache) stats() (gets, hits, puts int) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return c.gets, c.hits, c.puts
+}
+
 func generateNumbers(n int) []int {
 	s := make([]int, 0, n)
 
@@ -187,6 +311,79 @@ func (s *sleepWriter) Write(p []byte) (n int, err error) {
 	return s.sb.Write(p)
 }
 
+func (s *sleepWriter) String() string {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	return s.sb.String()
+}
+
+type shortWriter struct {
+	sb    *strings.Builder
+	mx    *sync.RWMutex
+	limit int
+}
+
+func newShortWriter(limit int) *shortWriter {
+	return &shortWriter{
+		sb:    new(strings.Builder),
+		mx:    new(sync.RWMutex),
+		limit: limit,
+	}
+}
+
+func (s *shortWriter) Write(p []byte) (n int, err error) {
+	runtime.Gosched()
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return s.sb.Write(p[:min(len(p), s.limit)])
+}
+
+func (s *shortWriter) String() string {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	return s.sb.String()
+}
+
+type recordingWriter struct {
+	sb    *strings.Builder
+	mx    *sync.RWMutex
+	calls []string
+}
+
+func newRecordingWriter() *recordingWriter {
+	return &recordingWriter{
+		sb: new(strings.Builder),
+		mx: new(sync.RWMutex),
+	}
+}
+
+func (r *recordingWriter) Write(p []byte) (n int, err error) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.calls = append(r.calls, string(p))
+
+	return r.sb.Write(p)
+}
+
+func (r *recordingWriter) String() string {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	return r.sb.String()
+}
+
+func (r *recordingWriter) Calls() []string {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	return slices.Clone(r.calls)
+}
+
 func newWriter() *concurrentWriter {
 	return &concurrentWriter{
 		sb: new(strings.Builder),
@@ -240,6 +437,38 @@ func parseLine(t *testing.T, line string) (int, []int) {
 	return left, right
 }
 
+// parseValueLine splits an "n: value" line produced by the number-theory batch APIs.
+func parseValueLine(t *testing.T, line string) (int, string) {
+	t.Helper()
+
+	left, right, found := strings.Cut(line, ": ")
+	require.True(t, found, "malformed line %q", line)
+
+	return strToInt(t, left), right
+}
+
+// minIntLine is the expected output line for math.MinInt: -1 followed by
+// strconv.IntSize-1 twos, since -math.MinInt does not fit into int.
+func minIntLine() string {
+	factors := make([]string, 0, 64)
+	factors = append(factors, "-1")
+
+	for range strconv.IntSize - 1 {
+		factors = append(factors, "2")
+	}
+
+	return strconv.Itoa(math.MinInt) + " = " + strings.Join(factors, " * ")
+}
+
+// bruteGCD is the Euclidean reference for number-theory helpers built on factorizations.
+func bruteGCD(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return max(a, -a)
+}
+
 func checkFactorization(num int, delimiters []int) bool {
 	if !slices.IsSortedFunc(delimiters, func(i, j int) int {
 		return i - j
@@ -302,3 +531,34 @@ func (pc *primeChecker) IsPrime(n int) bool {
 
 	return isPrime
 }
+
+func skipIfNot64Bit(t testing.TB) {
+	t.Helper()
+
+	if math.MaxInt != math.MaxInt64 {
+		t.Skip("large composites require 64-bit int")
+	}
+}
+
+// hardSemiprimes64 keeps the literals in uint64, so that util_test.go still
+// compiles on 32-bit platforms.
+var hardSemiprimes64 = []uint64{
+	1152921423002469787, // 1073741783 * 1073741789
+	4611685975477714963, // 2147483629 * 2147483647
+	9223372021822390277, // 2147483647 * 4294967291
+	999999866000004473,  // 999999929 * 999999937
+}
+
+// hardSemiprimes is empty on 32-bit platforms: every test using it calls skipIfNot64Bit.
+var hardSemiprimes = func() []int {
+	if strconv.IntSize != 64 {
+		return nil
+	}
+
+	s := make([]int, 0, len(hardSemiprimes64))
+	for _, n := range hardSemiprimes64 {
+		s = append(s, int(n))
+	}
+
+	return s
+}()
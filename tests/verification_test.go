@@ -0,0 +1,103 @@
+//go:build verification_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// poisonedCache serves fixed, possibly wrong, factorizations to exercise verification.
+type poisonedCache map[int][]int
+
+func (c poisonedCache) Get(n int) ([]int, bool) {
+	f, ok := c[n]
+
+	return slices.Clone(f), ok
+}
+
+func (c poisonedCache) Put(int, []int) {}
+
+func newVerifyingFactorizer(t *testing.T, cache FactorCache) *factorizerImpl {
+	t.Helper()
+
+	fact, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithFactorCache(cache),
+		WithVerification(),
+	)
+	require.NoError(t, err)
+
+	return fact
+}
+
+func TestVerificationPassesCorrectResults(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := append(generateNumbers(100_000), -1, -17, -100)
+
+	writer := newWriter()
+	err := newVerifyingFactorizer(t, poisonedCache{}).Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	require.Len(t, getFact(writer), len(numbers))
+}
+
+func TestVerificationFailed(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name    string
+		n       int
+		factors []int
+	}{
+		{name: "wrong product", n: 91, factors: []int{7, 11}},
+		{name: "composite factor", n: 91, factors: []int{91}},
+		{name: "missing sign", n: -91, factors: []int{7, 13}},
+		{name: "unsorted", n: 91, factors: []int{13, 7}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := poisonedCache{tt.n: tt.factors}
+
+			writer := newWriter()
+			err := newVerifyingFactorizer(t, cache).Factorize(context.Background(), []int{12, tt.n, 13}, writer)
+
+			var verr *VerificationError
+			require.True(t, errors.As(err, &verr), "got %v", err)
+			require.Equal(t, tt.n, verr.N)
+			require.Equal(t, tt.factors, verr.Factors)
+			require.NotErrorIs(t, err, ErrWriterInteraction)
+			require.NotErrorIs(t, err, ErrFactorizationCancelled)
+
+			for _, line := range getFact(writer) {
+				num, _ := parseLine(t, line)
+				require.NotEqual(t, tt.n, num, "unverified result must not be written")
+			}
+		})
+	}
+}
+
+func TestVerificationDisabledByDefault(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(1),
+		WithWriteWorkers(1),
+		WithFactorCache(poisonedCache{91: {7, 11}}),
+	)
+	require.NoError(t, err)
+
+	// without verification cache hits stay trusted
+	writer := newWriter()
+	err = fact.Factorize(context.Background(), []int{91}, writer)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"91 = 7 * 11"}, getFact(writer))
+}
@@ -0,0 +1,150 @@
+//go:build verify_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyValidOutput(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := append(generateNumbers(10_000), -1, -17, math.MaxInt32, math.MinInt+1)
+	fact := newFactorizer(t, 10, 10)
+
+	writer := newWriter()
+	err := fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	report := Verify(strings.NewReader(writer.String()))
+	require.NoError(t, report.Err)
+	require.Empty(t, report.Errors)
+	require.True(t, report.OK())
+	require.Equal(t, len(numbers), report.Lines)
+}
+
+func TestVerifyLine(t *testing.T) {
+	testCases := []struct {
+		name string
+		line string
+		err  error
+	}{
+		{name: "zero", line: "0 = 0"},
+		{name: "one", line: "1 = 1"},
+		{name: "minus one", line: "-1 = -1"},
+		{name: "negative", line: "-12 = -1 * 2 * 2 * 3"},
+		{name: "min int", line: minIntLine()},
+		{name: "empty", line: "", err: ErrMalformedLine},
+		{name: "no factors", line: "12 = ", err: ErrMalformedLine},
+		{name: "not a number", line: "abc = 2", err: ErrMalformedLine},
+		{name: "bad factor", line: "12 = 2 * x * 3", err: ErrMalformedLine},
+		{name: "composite factor", line: "12 = 2 * 6", err: ErrNotPrime},
+		{name: "negative factor", line: "-12 = -2 * 2 * 3", err: ErrNotPrime},
+		{name: "unsorted", line: "12 = 3 * 2 * 2", err: ErrNotSorted},
+		{name: "minus one not first", line: "-6 = 2 * -1 * 3", err: ErrNotSorted},
+		{name: "wrong product", line: "12 = 2 * 2 * 2", err: ErrProductMismatch},
+		{name: "missing sign", line: "-12 = 2 * 2 * 3", err: ErrProductMismatch},
+		{name: "overflowing product", line: "-50 = 2 * 9223372036854775783", err: ErrProductMismatch},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Verify(strings.NewReader(tt.line + "\n"))
+			require.NoError(t, report.Err)
+
+			if tt.err == nil {
+				require.True(t, report.OK())
+				require.Empty(t, report.Errors)
+
+				return
+			}
+
+			require.False(t, report.OK())
+			require.Len(t, report.Errors, 1)
+			require.Equal(t, 1, report.Errors[0].Line)
+			require.Equal(t, tt.line, report.Errors[0].Text)
+			require.ErrorIs(t, report.Errors[0], tt.err)
+		})
+	}
+}
+
+func TestVerifyReportsEveryBadLine(t *testing.T) {
+	output := strings.Join([]string{
+		"4 = 2 * 2",
+		"12 = 2 * 6",
+		"5 = 5",
+		"garbage",
+		"6 = 3 * 2",
+	}, "\n")
+
+	report := Verify(strings.NewReader(output))
+	require.NoError(t, report.Err)
+	require.False(t, report.OK())
+	require.Equal(t, 5, report.Lines)
+
+	lines := make([]int, 0, len(report.Errors))
+	for _, e := range report.Errors {
+		lines = append(lines, e.Line)
+	}
+
+	require.Equal(t, []int{2, 4, 5}, lines)
+}
+
+func TestVerifyInput(t *testing.T) {
+	testCases := []struct {
+		name    string
+		output  string
+		numbers []int
+		missing []int
+		extra   []int
+	}{
+		{
+			name:    "complete",
+			output:  "4 = 2 * 2\n5 = 5\n4 = 2 * 2\n",
+			numbers: []int{4, 4, 5},
+		},
+		{
+			name:    "missing",
+			output:  "4 = 2 * 2\n",
+			numbers: []int{4, 4, 5},
+			missing: []int{4, 5},
+		},
+		{
+			name:    "extra",
+			output:  "4 = 2 * 2\n5 = 5\n7 = 7\n",
+			numbers: []int{4, 5},
+			extra:   []int{7},
+		},
+		{
+			name:    "invalid line does not count",
+			output:  "4 = 2 * 2\n5 = 5\n6 = 6\n",
+			numbers: []int{4, 5, 6},
+			missing: []int{6},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			report := VerifyInput(strings.NewReader(tt.output), tt.numbers)
+			require.NoError(t, report.Err)
+
+			require.ElementsMatch(t, tt.missing, report.Missing)
+			require.ElementsMatch(t, tt.extra, report.Extra)
+		})
+	}
+}
+
+func TestVerifyReaderError(t *testing.T) {
+	errRead := errors.New("output file unavailable")
+
+	report := Verify(iotest.ErrReader(errRead))
+	require.ErrorIs(t, report.Err, errRead)
+	require.False(t, report.OK())
+}
@@ -0,0 +1,173 @@
+//go:build watermark_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type pressureRecorder struct {
+	mx     *sync.Mutex
+	events []MemoryPressure
+}
+
+func newPressureRecorder() *pressureRecorder {
+	return &pressureRecorder{
+		mx: new(sync.Mutex),
+	}
+}
+
+func (p *pressureRecorder) record(event MemoryPressure) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	p.events = append(p.events, event)
+}
+
+func (p *pressureRecorder) forWatermark(watermark int) []MemoryPressure {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	return slices.DeleteFunc(slices.Clone(p.events), func(e MemoryPressure) bool {
+		return e.Watermark != watermark
+	})
+}
+
+func requireAlternating(t *testing.T, events []MemoryPressure) {
+	t.Helper()
+
+	for i, e := range events {
+		require.Equal(t, i%2 == 0, e.Above, "event %d: %+v", i, e)
+
+		if e.Above {
+			require.GreaterOrEqual(t, e.InFlight, e.Watermark)
+		} else {
+			require.Less(t, e.InFlight, e.Watermark)
+		}
+	}
+}
+
+func TestMemoryWatermarkInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	t.Run("bytes", func(t *testing.T) {
+		_, err := New(WithMemoryWatermark(-1, false, func(MemoryPressure) {}))
+
+		require.ErrorContains(t, err, "watermark")
+		require.ErrorContains(t, err, "-1")
+	})
+
+	t.Run("handler", func(t *testing.T) {
+		_, err := New(WithMemoryWatermark(1024, false, nil))
+
+		require.ErrorContains(t, err, "watermark")
+	})
+}
+
+func TestMemoryWatermarkEvents(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const (
+		low  = 64
+		high = 1 << 30
+	)
+
+	recorder := newPressureRecorder()
+
+	fact, err := New(
+		WithFactorizationWorkers(100),
+		WithWriteWorkers(1),
+		WithMemoryWatermark(low, false, recorder.record),
+		WithMemoryWatermark(high, false, recorder.record),
+	)
+	require.NoError(t, err)
+
+	err = fact.Factorize(context.Background(), generateNumbers(200), newSleepWriter(time.Millisecond))
+	require.NoError(t, err)
+
+	events := recorder.forWatermark(low)
+	require.NotEmpty(t, events)
+	require.True(t, events[0].Above)
+	require.False(t, events[len(events)-1].Above, "pressure must be released once everything is written")
+	requireAlternating(t, events)
+
+	require.Empty(t, recorder.forWatermark(high))
+}
+
+func TestMemoryWatermarkNoPressure(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	recorder := newPressureRecorder()
+
+	fact, err := New(
+		WithFactorizationWorkers(1),
+		WithWriteWorkers(1),
+		WithMemoryWatermark(1<<20, false, recorder.record),
+	)
+	require.NoError(t, err)
+
+	err = fact.Factorize(context.Background(), generateNumbers(1000), newWriter())
+	require.NoError(t, err)
+
+	require.Empty(t, recorder.forWatermark(1<<20))
+}
+
+func TestMemoryWatermarkPauseIntake(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const watermark = 64
+
+	recorder := newPressureRecorder()
+
+	fact, err := New(
+		WithFactorizationWorkers(100),
+		WithWriteWorkers(2),
+		WithMemoryWatermark(watermark, true, recorder.record),
+	)
+	require.NoError(t, err)
+
+	numbers := generateNumbers(500)
+	writer := newSleepWriter(time.Millisecond)
+
+	err = fact.Factorize(context.Background(), numbers, writer)
+	require.NoError(t, err)
+
+	events := recorder.forWatermark(watermark)
+	require.NotEmpty(t, events)
+	requireAlternating(t, events)
+
+	allNums := make([]int, 0, len(numbers))
+	for _, line := range getFact(writer) {
+		num, res := parseLine(t, line)
+		require.True(t, checkFactorization(num, res))
+		allNums = append(allNums, num)
+	}
+
+	slices.Sort(allNums)
+	require.Equal(t, numbers, allNums)
+}
+
+func TestMemoryWatermarkCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	recorder := newPressureRecorder()
+
+	fact, err := New(
+		WithFactorizationWorkers(10),
+		WithWriteWorkers(1),
+		WithMemoryWatermark(1, true, recorder.record),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	err = fact.Factorize(ctx, generateNumbers(1000), newSleepWriter(time.Millisecond*50))
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+}
@@ -0,0 +1,121 @@
+//go:build worksteal_test
+
+package fact
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkStealingInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name string
+		size int
+		want string
+	}{
+		{name: "zero", size: 0, want: "0"},
+		{name: "negative", size: -1, want: "-1"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(WithChunkSize(tt.size))
+
+			require.ErrorContains(t, err, "chunk")
+			require.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestWorkStealingCorrectness(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	numbers := generateNumbers(10_000)
+
+	for _, size := range []int{1, 7, 1000, len(numbers), 1_000_000} {
+		fact, err := New(
+			WithFactorizationWorkers(10),
+			WithWriteWorkers(10),
+			WithChunkSize(size),
+		)
+		require.NoError(t, err)
+
+		writer := newWriter()
+		err = fact.Factorize(context.Background(), numbers, writer)
+		require.NoError(t, err)
+
+		allNums := make([]int, 0, len(numbers))
+		for _, line := range getFact(writer) {
+			num, res := parseLine(t, line)
+			require.True(t, checkFactorization(num, res))
+
+			allNums = append(allNums, num)
+		}
+
+		slices.Sort(allNums)
+		require.Equal(t, numbers, allNums, "chunk size %d", size)
+	}
+}
+
+func TestWorkStealingSplitsChunk(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	const (
+		factWorkers = 4
+		delay       = 2 * time.Millisecond
+	)
+
+	numbers := generateNumbers(200)
+
+	measure := func(factWorkers int) (time.Duration, []int) {
+		var mx sync.Mutex
+
+		taken := make([]int, factWorkers)
+
+		// the whole input is a single chunk, so only stealing spreads it across workers
+		fact, err := New(
+			WithFactorizationWorkers(factWorkers),
+			WithWriteWorkers(1),
+			WithChunkSize(len(numbers)),
+			WithFaultInjector(&scriptedFaults{
+				factorize: func(_ context.Context, worker, _ int) {
+					mx.Lock()
+					taken[worker]++
+					mx.Unlock()
+
+					// every number costs the same, whatever the factorization algorithm
+					time.Sleep(delay)
+				},
+			}),
+		)
+		require.NoError(t, err)
+
+		writer := newWriter()
+
+		start := time.Now()
+		err = fact.Factorize(context.Background(), numbers, writer)
+		elapsed := time.Since(start)
+
+		require.NoError(t, err)
+		require.Len(t, getFact(writer), len(numbers))
+
+		return elapsed, taken
+	}
+
+	alone, _ := measure(1)
+	stolen, taken := measure(factWorkers)
+
+	for worker, count := range taken {
+		require.Positive(t, count, "worker %d never stole from the chunk: %v", worker, taken)
+		require.Less(t, count, len(numbers)/2, "worker %d kept most of the chunk: %v", worker, taken)
+	}
+
+	require.Less(t, stolen, alone/2, "idle workers must steal from the chunk taken by the first one")
+}
@@ -0,0 +1,121 @@
+//go:build writebatch_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteBatchInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	_, err := New(WithWriteBatchSize(0))
+
+	require.ErrorContains(t, err, "batch")
+	require.ErrorContains(t, err, "0")
+}
+
+func TestWriteBatchCoalescesLines(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name         string
+		batch        int
+		writeWorkers int
+		count        int
+	}{
+		{name: "single line batches", batch: 1, writeWorkers: 1, count: 10},
+		{name: "partial last batch", batch: 10, writeWorkers: 1, count: 95},
+		{name: "batch larger than input", batch: 1000, writeWorkers: 1, count: 10},
+		{name: "several workers", batch: 16, writeWorkers: 4, count: 1000},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			fact, err := New(
+				WithFactorizationWorkers(4),
+				WithWriteWorkers(tt.writeWorkers),
+				WithWriteBatchSize(tt.batch),
+			)
+			require.NoError(t, err)
+
+			numbers := generateNumbers(tt.count)
+			writer := newRecordingWriter()
+
+			err = fact.Factorize(context.Background(), numbers, writer)
+			require.NoError(t, err)
+
+			calls := writer.Calls()
+			for _, call := range calls {
+				require.True(t, strings.HasSuffix(call, "\n"), "batch %q ends mid-line", call)
+				require.LessOrEqual(t, strings.Count(call, "\n"), tt.batch)
+			}
+
+			require.LessOrEqual(t, len(calls), (tt.count+tt.batch-1)/tt.batch+tt.writeWorkers)
+
+			allNums := make([]int, 0, len(numbers))
+			for _, line := range getFact(writer) {
+				num, res := parseLine(t, line)
+				require.True(t, checkFactorization(num, res))
+				allNums = append(allNums, num)
+			}
+
+			slices.Sort(allNums)
+			require.Equal(t, numbers, allNums)
+		})
+	}
+}
+
+func TestWriteBatchFlushOnCancel(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(1),
+		WithWriteWorkers(1),
+		WithWriteBatchSize(1_000_000_000),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	numbers := make([]int, 0, 1_000_000)
+	for range 1_000_000 {
+		numbers = append(numbers, 1073741789)
+	}
+
+	writer := newRecordingWriter()
+
+	err = fact.Factorize(ctx, numbers, writer)
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	require.NotEmpty(t, writer.Calls(), "partial batch must be flushed on cancellation")
+
+	for _, line := range getFact(writer) {
+		require.Equal(t, "1073741789 = 1073741789", line)
+	}
+}
+
+func TestWriteBatchWriterError(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(2),
+		WithWriteWorkers(2),
+		WithWriteBatchSize(100),
+	)
+	require.NoError(t, err)
+
+	errWrite := errors.New("upload failed")
+
+	err = fact.Factorize(context.Background(), generateNumbers(1000), newSleepErrorWriter(time.Millisecond, errWrite))
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errWrite)
+}
@@ -0,0 +1,81 @@
+//go:build writererror_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterErrorDetails(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	testCases := []struct {
+		name    string
+		numbers []int
+		want    map[int]string
+	}{
+		{
+			name:    "single number",
+			numbers: []int{100},
+			want: map[int]string{
+				100: "100 = 2 * 2 * 5 * 5\n",
+			},
+		},
+		{
+			name:    "negative",
+			numbers: []int{-17},
+			want: map[int]string{
+				-17: "-17 = -1 * 17\n",
+			},
+		},
+		{
+			name:    "several numbers",
+			numbers: []int{25, 38, 0},
+			want: map[int]string{
+				25: "25 = 5 * 5\n",
+				38: "38 = 2 * 19\n",
+				0:  "0 = 0\n",
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			errWrite := errors.New("sink unavailable")
+			fact := newFactorizer(t, 2, 2)
+
+			err := fact.Factorize(context.Background(), tt.numbers, newSleepErrorWriter(time.Millisecond, errWrite))
+			require.ErrorIs(t, err, ErrWriterInteraction)
+			require.ErrorIs(t, err, errWrite)
+
+			var writerErr *WriterError
+			require.ErrorAs(t, err, &writerErr)
+			require.Contains(t, tt.want, writerErr.N)
+			require.Equal(t, tt.want[writerErr.N], string(writerErr.Line))
+			require.ErrorIs(t, writerErr.Err, errWrite)
+			require.ErrorIs(t, writerErr, ErrWriterInteraction)
+			require.ErrorContains(t, writerErr, strconv.Itoa(writerErr.N))
+		})
+	}
+}
+
+func TestWriterErrorOnlyForWriterFailures(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fact := newFactorizer(t, 1, 1)
+
+	err := fact.Factorize(ctx, []int{1, 2, 3}, newWriter())
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	var writerErr *WriterError
+	require.False(t, errors.As(err, &writerErr))
+}
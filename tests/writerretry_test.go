@@ -0,0 +1,157 @@
+//go:build writerretry_test
+
+package fact
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errTransient = errors.New("connection reset")
+
+type flakyWriter struct {
+	sb       *strings.Builder
+	mx       *sync.RWMutex
+	failures int
+	attempts map[string]int
+	calls    atomic.Int64
+}
+
+func newFlakyWriter(failures int) *flakyWriter {
+	return &flakyWriter{
+		sb:       new(strings.Builder),
+		mx:       new(sync.RWMutex),
+		failures: failures,
+		attempts: make(map[string]int),
+	}
+}
+
+func (f *flakyWriter) Write(p []byte) (n int, err error) {
+	f.calls.Add(1)
+
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	f.attempts[string(p)]++
+	if f.attempts[string(p)] <= f.failures {
+		return 0, errTransient
+	}
+
+	return f.sb.Write(p)
+}
+
+func (f *flakyWriter) String() string {
+	f.mx.RLock()
+	defer f.mx.RUnlock()
+
+	return f.sb.String()
+}
+
+func TestWriterRetryInvalidOptions(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	t.Run("attempts", func(t *testing.T) {
+		_, err := New(WithWriterRetry(0, time.Millisecond))
+
+		require.ErrorContains(t, err, "retry")
+		require.ErrorContains(t, err, "0")
+	})
+
+	t.Run("backoff", func(t *testing.T) {
+		_, err := New(WithWriterRetry(3, -time.Second))
+
+		require.ErrorContains(t, err, "retry")
+		require.ErrorContains(t, err, "-1s")
+	})
+}
+
+func TestWriterRetryRecovers(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(5),
+		WithWriteWorkers(5),
+		WithWriterRetry(3, time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	writer := newFlakyWriter(2)
+	err = fact.Factorize(context.Background(), []int{100, -17, 25, 38}, writer)
+	require.NoError(t, err)
+
+	facts := getFact(writer)
+	slices.Sort(facts)
+	require.Equal(t, []string{
+		"-17 = -1 * 17",
+		"100 = 2 * 2 * 5 * 5",
+		"25 = 5 * 5",
+		"38 = 2 * 19",
+	}, facts)
+	require.EqualValues(t, 12, writer.calls.Load())
+}
+
+func TestWriterRetryExhausted(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(1),
+		WithWriteWorkers(1),
+		WithWriterRetry(2, time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	writer := newFlakyWriter(2)
+	err = fact.Factorize(context.Background(), []int{4}, writer)
+	require.ErrorIs(t, err, ErrWriterInteraction)
+	require.ErrorIs(t, err, errTransient)
+
+	require.EqualValues(t, 2, writer.calls.Load())
+	require.Empty(t, getFact(writer))
+}
+
+func TestWriterRetryBackoff(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(1),
+		WithWriteWorkers(1),
+		WithWriterRetry(3, time.Millisecond*50),
+	)
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	err = fact.Factorize(context.Background(), []int{4}, newSleepErrorWriter(0, errTransient))
+	require.ErrorIs(t, err, ErrWriterInteraction)
+
+	require.GreaterOrEqual(t, time.Since(start), time.Millisecond*100)
+}
+
+func TestWriterRetryCancelDuringBackoff(t *testing.T) {
+	deferrableLeakDetection(t)
+
+	fact, err := New(
+		WithFactorizationWorkers(1),
+		WithWriteWorkers(1),
+		WithWriterRetry(10, time.Second*10),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	t.Cleanup(cancel)
+
+	start := time.Now()
+
+	err = fact.Factorize(ctx, []int{4}, newSleepErrorWriter(0, errTransient))
+	require.ErrorIs(t, err, ErrFactorizationCancelled)
+
+	require.Less(t, time.Since(start), time.Second)
+}